@@ -0,0 +1,211 @@
+package echoerror
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"sync"
+	"time"
+)
+
+// ErrLookupPanicked is returned to the callers waiting on a lookup that
+// panicked.
+var ErrLookupPanicked = errors.New("echoerror: translation lookup panicked")
+
+// TranslationLookup loads the message of code in locale, e.g. from a
+// translation table.
+type TranslationLookup func(ctx context.Context, locale, code string) (string, error)
+
+type CacheConfig struct {
+	// Lookup loads the messages missing in the cache.
+	Lookup TranslationLookup
+	// Locale returns the locale of a request, e.g. Localizer.Locale. Defaults
+	// to the preferred language of Accept-Language, then DefaultLocale.
+	Locale func(c echo.Context) string
+	// DefaultLocale is used for requests without a locale. Defaults to "en".
+	DefaultLocale string
+	// TTL is how long a message is cached. Defaults to 5 minutes.
+	TTL time.Duration
+	// ErrorTTL is how long a failed lookup is cached, so a missing message
+	// does not reach Lookup on every error. Failures are not cached when zero.
+	ErrorTTL time.Duration
+	// Size is the number of messages kept; the least recently used one is
+	// evicted. Defaults to 1024.
+	Size int
+}
+
+// LocalizeCache caches the messages of a TranslationLookup by locale and code.
+// Concurrent misses of the same message share a single lookup. It is safe for
+// concurrent use.
+type LocalizeCache struct {
+	config   CacheConfig
+	mu       sync.Mutex
+	entries  map[cacheKey]*list.Element
+	lru      *list.List
+	inflight map[cacheKey]*cacheCall
+	// generation is incremented by Purge, so lookups started before are not
+	// cached.
+	generation uint64
+}
+
+type cacheKey struct {
+	locale string
+	code   string
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	message string
+	err     error
+	expires time.Time
+}
+
+// cacheCall is a lookup in flight, waited on by the concurrent misses of its
+// key.
+type cacheCall struct {
+	done       chan struct{}
+	generation uint64
+	message    string
+	err        error
+}
+
+// Localize returns the message of code in the locale of c. It can be used as
+// I18n.Localize.
+func (l *LocalizeCache) Localize(c echo.Context, code string) (string, error) {
+	locale := l.locale(c)
+	message, err := l.Get(context.WithoutCancel(c.Request().Context()), locale, code)
+	if err == nil {
+		SetLocale(c, locale)
+	}
+	return message, err
+}
+
+// I18n returns an I18n configuration localizing with l.
+func (l *LocalizeCache) I18n() *I18n {
+	return &I18n{
+		Enabled:  true,
+		Localize: l.Localize,
+	}
+}
+
+// Get returns the cached message of code in locale, looking it up on a miss.
+func (l *LocalizeCache) Get(ctx context.Context, locale, code string) (string, error) {
+	key := cacheKey{locale: NormalizeLocale(locale), code: code}
+
+	l.mu.Lock()
+	if el, ok := l.entries[key]; ok {
+		e := el.Value.(*cacheEntry)
+		if time.Now().Before(e.expires) {
+			l.lru.MoveToFront(el)
+			l.mu.Unlock()
+			return e.message, e.err
+		}
+		l.remove(el)
+	}
+	if call, ok := l.inflight[key]; ok {
+		l.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.message, call.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	call := &cacheCall{done: make(chan struct{}), generation: l.generation}
+	l.inflight[key] = call
+	l.mu.Unlock()
+
+	l.lookup(ctx, key, call)
+	return call.message, call.err
+}
+
+// lookup runs Lookup for the call and caches its result. The waiters of the
+// call are released even when Lookup panics, with ErrLookupPanicked.
+func (l *LocalizeCache) lookup(ctx context.Context, key cacheKey, call *cacheCall) {
+	returned := false
+	defer func() {
+		l.mu.Lock()
+		delete(l.inflight, key)
+		if !returned {
+			call.message, call.err = "", ErrLookupPanicked
+		} else {
+			ttl := l.config.TTL
+			if call.err != nil {
+				ttl = l.config.ErrorTTL
+			}
+			if ttl > 0 && call.generation == l.generation {
+				l.add(&cacheEntry{key: key, message: call.message, err: call.err, expires: time.Now().Add(ttl)})
+			}
+		}
+		l.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.message, call.err = l.config.Lookup(ctx, key.locale, key.code)
+	returned = true
+}
+
+// Purge drops every cached message, e.g. after translations were edited.
+func (l *LocalizeCache) Purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = map[cacheKey]*list.Element{}
+	l.lru.Init()
+	l.generation++
+}
+
+// Len returns the number of cached messages, including expired ones not yet
+// evicted.
+func (l *LocalizeCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lru.Len()
+}
+
+func (l *LocalizeCache) add(e *cacheEntry) {
+	if el, ok := l.entries[e.key]; ok {
+		l.remove(el)
+	}
+	for l.lru.Len() >= l.config.Size {
+		l.remove(l.lru.Back())
+	}
+	l.entries[e.key] = l.lru.PushFront(e)
+}
+
+func (l *LocalizeCache) remove(el *list.Element) {
+	delete(l.entries, el.Value.(*cacheEntry).key)
+	l.lru.Remove(el)
+}
+
+func (l *LocalizeCache) locale(c echo.Context) string {
+	if l.config.Locale != nil {
+		if locale := l.config.Locale(c); locale != "" {
+			return locale
+		}
+		return l.config.DefaultLocale
+	}
+	if locales := ParseAcceptLanguage(c.Request().Header.Get(HeaderAcceptLanguage)); len(locales) > 0 {
+		return NormalizeLocale(locales[0])
+	}
+	return l.config.DefaultLocale
+}
+
+// NewLocalizeCache returns a LocalizeCache in front of config.Lookup.
+func NewLocalizeCache(config CacheConfig) *LocalizeCache {
+	if config.DefaultLocale == "" {
+		config.DefaultLocale = "en"
+	}
+	if config.TTL <= 0 {
+		config.TTL = 5 * time.Minute
+	}
+	if config.Size <= 0 {
+		config.Size = 1024
+	}
+	return &LocalizeCache{
+		config:   config,
+		entries:  map[cacheKey]*list.Element{},
+		lru:      list.New(),
+		inflight: map[cacheKey]*cacheCall{},
+	}
+}
@@ -0,0 +1,161 @@
+package echoerror_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLocalizeCache(t *testing.T) {
+	var lookups atomic.Int64
+	cache := echoerror.NewLocalizeCache(echoerror.CacheConfig{
+		Lookup: func(ctx context.Context, locale, code string) (string, error) {
+			lookups.Add(1)
+			if code == "CUS999" {
+				return "", echoerror.ErrMessageNotFound
+			}
+			return locale + ":" + code, nil
+		},
+		TTL:      50 * time.Millisecond,
+		ErrorTTL: time.Minute,
+		Size:     2,
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if message, err := cache.Get(ctx, "th_th", "CUS001"); err != nil || message != "th-TH:CUS001" {
+			t.Error("Error", message, err)
+		}
+		if _, err := cache.Get(ctx, "th", "CUS999"); err != echoerror.ErrMessageNotFound {
+			t.Error("Error", err)
+		}
+	}
+	if lookups.Load() != 2 {
+		t.Error("Error", lookups.Load())
+	}
+
+	_, _ = cache.Get(ctx, "en", "CUS001")
+	if cache.Len() != 2 {
+		t.Error("Error", cache.Len())
+	}
+	_, _ = cache.Get(ctx, "th-TH", "CUS001")
+	if lookups.Load() != 4 {
+		t.Error("Error", lookups.Load())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	_, _ = cache.Get(ctx, "th-TH", "CUS001")
+	if lookups.Load() != 5 {
+		t.Error("Error", lookups.Load())
+	}
+
+	cache.Purge()
+	_, _ = cache.Get(ctx, "th-TH", "CUS001")
+	if lookups.Load() != 6 || cache.Len() != 1 {
+		t.Error("Error", lookups.Load(), cache.Len())
+	}
+}
+
+func TestLocalizeCacheSingleLookup(t *testing.T) {
+	var lookups atomic.Int64
+	release := make(chan struct{})
+	cache := echoerror.NewLocalizeCache(echoerror.CacheConfig{
+		Lookup: func(ctx context.Context, locale, code string) (string, error) {
+			lookups.Add(1)
+			<-release
+			return "Custom error", nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if message, _ := cache.Get(context.Background(), "en", "CUS001"); message != "Custom error" {
+				t.Error("Error", message)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if lookups.Load() != 1 {
+		t.Error("Error", lookups.Load())
+	}
+}
+
+func TestLocalizeCacheResponse(t *testing.T) {
+	app := echo.New()
+	customResp := NewCustomResponse()
+	cache := echoerror.NewLocalizeCache(echoerror.CacheConfig{
+		Lookup: func(ctx context.Context, locale, code string) (string, error) {
+			return "ข้อผิดพลาดที่กำหนดเอง", nil
+		},
+	})
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+		I18n:   cache.I18n(),
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echoerror.HeaderAcceptLanguage, "th-TH,th;q=0.9")
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = res.With(c).Response(NewCustomError())
+
+	actual := goerror.Body{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if actual.Message != "ข้อผิดพลาดที่กำหนดเอง" || resp.Header().Get(echoerror.HeaderContentLanguage) != "th-TH" {
+		t.Error("Error", resp.Header(), resp.Body.String())
+	}
+}
+
+func TestLocalizeCachePanic(t *testing.T) {
+	var lookups atomic.Int64
+	release := make(chan struct{})
+	cache := echoerror.NewLocalizeCache(echoerror.CacheConfig{
+		Lookup: func(ctx context.Context, locale, code string) (string, error) {
+			if lookups.Add(1) == 1 {
+				<-release
+				panic("database down")
+			}
+			return "Custom error", nil
+		},
+	})
+
+	waited := make(chan error, 1)
+	go func() {
+		defer func() { _ = recover() }()
+		_, _ = cache.Get(context.Background(), "en", "CUS001")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	go func() {
+		_, err := cache.Get(context.Background(), "en", "CUS001")
+		waited <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-waited:
+		if err != echoerror.ErrLookupPanicked {
+			t.Error("Error", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Error: waiter blocked")
+	}
+	if message, err := cache.Get(context.Background(), "en", "CUS001"); err != nil || message != "Custom error" {
+		t.Error("Error", message, err)
+	}
+}
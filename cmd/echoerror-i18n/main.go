@@ -0,0 +1,19 @@
+// Command echoerror-i18n checks the translation files of an echoerror
+// Localizer against the goerror codes used in Go source, see package
+// i18ncheck.
+//
+// It exits with status 1 when there is any issue. Only JSON translation files
+// are supported; it exits with status 2 when the directory holds YAML or TOML
+// files. Use i18ncheck.Run with their decoders to check those.
+//
+//	echoerror-i18n -src . -dir i18n -default-locale en
+package main
+
+import (
+	"github.com/prongbang/echoerror/i18ncheck"
+	"os"
+)
+
+func main() {
+	os.Exit(i18ncheck.Run(os.Args[1:], os.Stdout, os.Stderr, nil))
+}
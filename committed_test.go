@@ -0,0 +1,66 @@
+package echoerror_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func streamingHandler(res echoerror.Response) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlain)
+		c.Response().WriteHeader(http.StatusOK)
+		_, _ = c.Response().Write([]byte("chunk-1\n"))
+		c.Response().Flush()
+		return res.With(c).Response(goerror.NewServiceUnavailable())
+	}
+}
+
+func TestCommittedResponse(t *testing.T) {
+	recent := echoerror.NewRecentErrors(1)
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Observers: []echoerror.Observer{recent},
+	})
+	app.GET("/stream", streamingHandler(res))
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK || resp.Body.String() != "chunk-1\n" {
+		t.Error("Error", resp.Code, resp.Body.String())
+	}
+	entries := recent.Entries(echoerror.RecentFilter{})
+	if len(entries) != 1 || !entries[0].Committed || entries[0].Status != http.StatusServiceUnavailable {
+		t.Error("Error", entries)
+	}
+}
+
+func TestCommittedResponseAbort(t *testing.T) {
+	app := echo.New()
+	app.Use(middleware.Recover())
+	res := echoerror.New(&echoerror.Config{
+		AbortOnCommitted: true,
+	})
+	app.GET("/stream", streamingHandler(res))
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	r, err := http.Get(server.URL + "/stream")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		t.Error("Error", string(body))
+	}
+}
@@ -0,0 +1,70 @@
+package echoerror
+
+import (
+	"context"
+	"errors"
+	"github.com/prongbang/goerror"
+	"net"
+	"net/http"
+)
+
+const (
+	// StatusClientClosedRequest is the non-standard status of a request the
+	// client canceled before the response was written.
+	StatusClientClosedRequest = 499
+	CodeClientClosedRequest   = "CLE499"
+)
+
+type ClientClosedRequest struct {
+	goerror.Body
+}
+
+// Error implements error.
+func (c *ClientClosedRequest) Error() string {
+	return c.Message
+}
+
+func NewClientClosedRequest() error {
+	return &ClientClosedRequest{
+		Body: goerror.Body{
+			Code:    CodeClientClosedRequest,
+			Message: "Client Closed Request",
+		},
+	}
+}
+
+// DeadlineExceeded is rendered for deadlines and network timeouts, with the
+// code and message of a GatewayTimeout.
+type DeadlineExceeded struct {
+	goerror.Body
+}
+
+// Error implements error.
+func (d *DeadlineExceeded) Error() string {
+	return d.Message
+}
+
+func NewDeadlineExceeded() error {
+	return &DeadlineExceeded{
+		Body: goerror.Body{
+			Code:    goerror.CodeGatewayTimeout,
+			Message: http.StatusText(http.StatusGatewayTimeout),
+		},
+	}
+}
+
+// mapContextError translates canceled requests into ClientClosedRequest and
+// deadlines and network timeouts into DeadlineExceeded.
+func mapContextError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return NewClientClosedRequest()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NewDeadlineExceeded()
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return NewDeadlineExceeded()
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+package echoerror_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestContextErrors(t *testing.T) {
+	recent := echoerror.NewRecentErrors(1)
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Observers: []echoerror.Observer{recent},
+	})
+
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{fmt.Errorf("query users: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{&net.OpError{Op: "dial", Err: timeoutError{}}, http.StatusGatewayTimeout},
+		{fmt.Errorf("load: %w", context.Canceled), echoerror.StatusClientClosedRequest},
+		{&net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := app.NewContext(req, resp)
+
+		_ = res.With(c).Response(tc.err)
+
+		if resp.Code != tc.code {
+			t.Error("Error", tc.err, resp.Code)
+		}
+	}
+
+	entries := recent.Entries(echoerror.RecentFilter{})
+	if len(entries) != 1 || entries[0].Status != http.StatusBadRequest || entries[0].Message != "dial: connection refused" {
+		t.Error("Error", entries)
+	}
+}
+
+func TestCanceledStatus(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		CanceledStatus: http.StatusRequestTimeout,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = res.With(c).Response(context.Canceled)
+
+	if resp.Code != http.StatusRequestTimeout {
+		t.Error("Error", resp.Code)
+	}
+}
+
+func TestDeadlineStatus(t *testing.T) {
+	app := echo.New()
+	app.Use(echoerror.Use(echoerror.New()))
+	app.GET("/", func(c echo.Context) error {
+		return echoerror.Respond(c, fmt.Errorf("query users: %w", context.DeadlineExceeded))
+	})
+	app.GET("/upstream", func(c echo.Context) error {
+		return echoerror.Respond(c, goerror.NewGatewayTimeout())
+	})
+	api := app.Group("/api", echoerror.Override(&echoerror.Config{
+		DeadlineStatus: http.StatusServiceUnavailable,
+	}))
+	api.GET("", func(c echo.Context) error {
+		return echoerror.Respond(c, &net.OpError{Op: "read", Err: timeoutError{}})
+	})
+	api.GET("/upstream", func(c echo.Context) error {
+		return echoerror.Respond(c, goerror.NewGatewayTimeout())
+	})
+
+	cases := []struct {
+		target string
+		code   int
+	}{
+		{"/", http.StatusGatewayTimeout},
+		{"/upstream", http.StatusGatewayTimeout},
+		{"/api", http.StatusServiceUnavailable},
+		{"/api/upstream", http.StatusGatewayTimeout},
+	}
+	for _, tc := range cases {
+		resp := httptest.NewRecorder()
+		app.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		actual := goerror.Body{}
+		_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+		if resp.Code != tc.code || actual.Code != goerror.CodeGatewayTimeout {
+			t.Error("Error", tc.target, resp.Code, resp.Body.String())
+		}
+	}
+}
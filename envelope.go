@@ -0,0 +1,140 @@
+package echoerror
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"strings"
+	"unicode"
+)
+
+// Casing is the naming convention applied to body field names.
+type Casing int
+
+const (
+	CasingNone Casing = iota
+	CasingCamel
+	CasingPascal
+	CasingSnake
+	CasingKebab
+)
+
+// Envelope declares the shape of the JSON body written for an error.
+//
+// The zero value renders the body unchanged:
+//
+//	{"code": "CLE004", "message": "Not Found", "data": null}
+//
+// Key nests the body, Fields renames body fields, Static adds fixed top-level
+// fields and Casing converts the names of the remaining body fields:
+//
+//	&Envelope{Key: "error", Static: map[string]any{"success": false}}
+//	{"success": false, "error": {"code": "CLE004", "message": "Not Found", "data": null}}
+type Envelope struct {
+	// Key nests the body under this field. Dots nest deeper, e.g. "response.error".
+	Key string
+	// Fields renames body fields by their JSON name, e.g. {"message": "detail"}.
+	Fields map[string]string
+	// Omit drops body fields by their JSON name, e.g. ["data"].
+	Omit []string
+	// Static adds fixed fields at the top level.
+	Static map[string]any
+	// Casing converts body field names that are not renamed by Fields.
+	Casing Casing
+}
+
+// Render implements Renderer.
+func (e *Envelope) Render(c echo.Context, code int, err error) error {
+	return c.JSON(code, e.Wrap(err))
+}
+
+// Wrap returns the body of err reshaped by the envelope.
+func (e *Envelope) Wrap(err error) map[string]any {
+	body := map[string]any{}
+	if b, e1 := json.Marshal(err); e1 == nil {
+		_ = json.Unmarshal(b, &body)
+	}
+	if len(body) == 0 {
+		body["message"] = err.Error()
+	}
+	for _, name := range e.Omit {
+		delete(body, name)
+	}
+
+	shaped := make(map[string]any, len(body))
+	for name, value := range body {
+		if renamed, ok := e.Fields[name]; ok {
+			shaped[renamed] = value
+			continue
+		}
+		shaped[e.Casing.Convert(name)] = value
+	}
+
+	out := shaped
+	if e.Key != "" {
+		keys := strings.Split(e.Key, ".")
+		for i := len(keys) - 1; i >= 0; i-- {
+			out = map[string]any{keys[i]: out}
+		}
+	}
+	for name, value := range e.Static {
+		out[name] = value
+	}
+	return out
+}
+
+// Convert returns name in the casing.
+func (c Casing) Convert(name string) string {
+	if c == CasingNone {
+		return name
+	}
+	words := splitWords(name)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	switch c {
+	case CasingSnake:
+		return strings.Join(words, "_")
+	case CasingKebab:
+		return strings.Join(words, "-")
+	case CasingCamel, CasingPascal:
+		for i, w := range words {
+			if i == 0 && c == CasingCamel || w == "" {
+				continue
+			}
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+		return strings.Join(words, "")
+	}
+	return name
+}
+
+// splitWords splits an identifier on separators and lower-to-upper case changes.
+func splitWords(s string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
@@ -0,0 +1,94 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnvelopeNested(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Envelope: &echoerror.Envelope{
+			Key:    "error",
+			Omit:   []string{"data"},
+			Static: map[string]any{"success": false},
+		},
+	})
+
+	handler := func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewForbidden())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = handler(c)
+
+	if resp.Code != http.StatusForbidden {
+		t.Error("Error", resp.Code)
+	}
+	expected := `{"error":{"code":"CLE003","message":"Forbidden"},"success":false}`
+	if actual := resp.Body.String(); actual != expected+"\n" {
+		t.Error("Error", actual)
+	}
+}
+
+func TestEnvelopeFlatCustom(t *testing.T) {
+	app := echo.New()
+	customResp := echoerror.Custom(&validationResponse{})
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+		Envelope: &echoerror.Envelope{
+			Fields: map[string]string{"message": "detail"},
+			Casing: echoerror.CasingPascal,
+		},
+	})
+
+	handler := func(c echo.Context) error {
+		return res.With(c).Response(&ValidationError{
+			Body:   goerror.Body{Code: "VAL001", Message: "Validation failed"},
+			Fields: map[string]string{"email": "Invalid email format"},
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = handler(c)
+
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Error", resp.Code)
+	}
+	actual := map[string]any{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if actual["Code"] != "VAL001" || actual["detail"] != "Validation failed" || actual["Fields"] == nil {
+		t.Error("Error", resp.Body.String())
+	}
+}
+
+func TestCasingConvert(t *testing.T) {
+	cases := []struct {
+		casing   echoerror.Casing
+		name     string
+		expected string
+	}{
+		{echoerror.CasingSnake, "requestID", "request_id"},
+		{echoerror.CasingSnake, "HTTPStatus", "http_status"},
+		{echoerror.CasingCamel, "error_code", "errorCode"},
+		{echoerror.CasingPascal, "error-code", "ErrorCode"},
+		{echoerror.CasingKebab, "errorCode", "error-code"},
+		{echoerror.CasingNone, "error_Code", "error_Code"},
+	}
+	for _, tc := range cases {
+		if actual := tc.casing.Convert(tc.name); actual != tc.expected {
+			t.Error("Error", tc.name, actual)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package echoerror
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	uuidPattern   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexPattern    = regexp.MustCompile(`\b0x[0-9a-fA-F]+\b|\b[0-9a-fA-F]{8,}\b`)
+	numberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+	quotedPattern = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	spacePattern  = regexp.MustCompile(`\s+`)
+)
+
+// Fingerprint returns a stable identifier of an error built from its type,
+// code, route and normalized message, so the same failure with different
+// IDs or values shares one fingerprint.
+func Fingerprint(e Event) string {
+	h := sha1.New()
+	for _, part := range []string{errorType(e.Err), e.Code, e.Method, e.Route, NormalizeMessage(e.Message)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// NormalizeMessage replaces quoted strings, UUIDs, hexadecimal IDs and numbers
+// of a message with placeholders.
+func NormalizeMessage(message string) string {
+	message = quotedPattern.ReplaceAllString(message, "<str>")
+	message = uuidPattern.ReplaceAllString(message, "<uuid>")
+	message = hexPattern.ReplaceAllString(message, "<hex>")
+	message = numberPattern.ReplaceAllString(message, "<num>")
+	return strings.TrimSpace(spacePattern.ReplaceAllString(message, " "))
+}
+
+func errorType(err error) string {
+	if err == nil {
+		return ""
+	}
+	return reflect.TypeOf(err).String()
+}
+
+// FingerprintStats counts the occurrences of one fingerprint.
+type FingerprintStats struct {
+	Fingerprint string    `json:"fingerprint"`
+	Type        string    `json:"type"`
+	Code        string    `json:"code"`
+	Route       string    `json:"route"`
+	Status      int       `json:"status"`
+	Message     string    `json:"message"`
+	Count       int64     `json:"count"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// Fingerprints counts rendered errors per fingerprint. It is safe for
+// concurrent use.
+type Fingerprints struct {
+	mu    sync.Mutex
+	stats map[string]*FingerprintStats
+	limit int
+}
+
+// Observe implements Observer.
+func (f *Fingerprints) Observe(c echo.Context, e Event) {
+	f.Record(e)
+}
+
+// Record counts e and returns the updated stats of its fingerprint. When the
+// store is full, the least recently seen fingerprint is evicted.
+func (f *Fingerprints) Record(e Event) FingerprintStats {
+	fp := e.Fingerprint
+	if fp == "" {
+		fp = Fingerprint(e)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.stats[fp]
+	if !ok {
+		if f.limit > 0 && len(f.stats) >= f.limit {
+			f.evict()
+		}
+		s = &FingerprintStats{
+			Fingerprint: fp,
+			Type:        errorType(e.Err),
+			Code:        e.Code,
+			Route:       e.Route,
+			Status:      e.Status,
+			Message:     NormalizeMessage(e.Message),
+			FirstSeen:   e.Time,
+		}
+		f.stats[fp] = s
+	}
+	s.Count++
+	s.LastSeen = e.Time
+	return *s
+}
+
+// Get returns the stats of a fingerprint.
+func (f *Fingerprints) Get(fingerprint string) (FingerprintStats, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.stats[fingerprint]
+	if !ok {
+		return FingerprintStats{}, false
+	}
+	return *s, true
+}
+
+// List returns the stats of every fingerprint, most frequent first.
+func (f *Fingerprints) List() []FingerprintStats {
+	f.mu.Lock()
+	result := make([]FingerprintStats, 0, len(f.stats))
+	for _, s := range f.stats {
+		result = append(result, *s)
+	}
+	f.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Fingerprint < result[j].Fingerprint
+	})
+	return result
+}
+
+// Handler returns an Echo handler that lists the stats as JSON, or the stats of
+// the fingerprint given by the query parameter fingerprint.
+func (f *Fingerprints) Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if fp := c.QueryParam("fingerprint"); fp != "" {
+			s, ok := f.Get(fp)
+			if !ok {
+				return Respond(c, goerror.NewNotFound())
+			}
+			return c.JSON(http.StatusOK, s)
+		}
+		return c.JSON(http.StatusOK, f.List())
+	}
+}
+
+func (f *Fingerprints) evict() {
+	oldest := ""
+	for fp, s := range f.stats {
+		if oldest == "" || s.LastSeen.Before(f.stats[oldest].LastSeen) {
+			oldest = fp
+		}
+	}
+	delete(f.stats, oldest)
+}
+
+// NewFingerprints returns a Fingerprints store keeping at most limit
+// fingerprints. A limit of 0 keeps every fingerprint.
+func NewFingerprints(limit int) *Fingerprints {
+	return &Fingerprints{
+		stats: map[string]*FingerprintStats{},
+		limit: limit,
+	}
+}
+
+type firstOccurrence struct {
+	mu     sync.Mutex
+	window time.Duration
+	next   Observer
+	last   map[string]time.Time
+}
+
+// Observe implements Observer.
+func (o *firstOccurrence) Observe(c echo.Context, e Event) {
+	fp := e.Fingerprint
+	if fp == "" {
+		fp = Fingerprint(e)
+	}
+
+	o.mu.Lock()
+	last, seen := o.last[fp]
+	emit := !seen || e.Time.Sub(last) >= o.window
+	if emit {
+		o.last[fp] = e.Time
+		if len(o.last) > 1024 {
+			for k, t := range o.last {
+				if e.Time.Sub(t) >= o.window {
+					delete(o.last, k)
+				}
+			}
+		}
+	}
+	o.mu.Unlock()
+
+	if emit {
+		o.next.Observe(c, e)
+	}
+}
+
+// FirstOccurrence returns an Observer that forwards an event to next only when
+// its fingerprint was not forwarded within the window.
+func FirstOccurrence(window time.Duration, next Observer) Observer {
+	return &firstOccurrence{
+		window: window,
+		next:   next,
+		last:   map[string]time.Time{},
+	}
+}
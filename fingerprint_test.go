@@ -0,0 +1,114 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFingerprintNormalizesMessage(t *testing.T) {
+	a := echoerror.Event{Route: "/orders/:id", Code: "ORD001", Message: "order 1234 of user 'alice' not found"}
+	b := echoerror.Event{Route: "/orders/:id", Code: "ORD001", Message: "order 98 of user 'bob' not found"}
+	c := echoerror.Event{Route: "/users/:id", Code: "ORD001", Message: "order 98 of user 'bob' not found"}
+
+	if echoerror.Fingerprint(a) != echoerror.Fingerprint(b) {
+		t.Error("Error", echoerror.Fingerprint(a), echoerror.Fingerprint(b))
+	}
+	if echoerror.Fingerprint(b) == echoerror.Fingerprint(c) {
+		t.Error("Error", echoerror.Fingerprint(b))
+	}
+	if actual := echoerror.NormalizeMessage("job 550e8400-e29b-41d4-a716-446655440000 failed after 3 tries"); actual != "job <uuid> failed after <num> tries" {
+		t.Error("Error", actual)
+	}
+}
+
+func TestFingerprints(t *testing.T) {
+	fingerprints := echoerror.NewFingerprints(0)
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Observers: []echoerror.Observer{fingerprints},
+	})
+
+	app.GET("/users/:id", func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewNotFound())
+	})
+	app.GET("/boom", func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewInternalServerError())
+	})
+	app.GET("/admin/fingerprints", fingerprints.Handler())
+
+	for _, path := range []string{"/users/1", "/users/2", "/boom", "/users/3"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		app.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	stats := fingerprints.List()
+	if len(stats) != 2 || stats[0].Count != 3 || stats[0].Route != "/users/:id" || stats[1].Count != 1 {
+		t.Fatal("Error", stats)
+	}
+	if stats[0].LastSeen.Before(stats[0].FirstSeen) {
+		t.Error("Error", stats[0])
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/fingerprints?fingerprint="+stats[1].Fingerprint, nil)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	actual := echoerror.FingerprintStats{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if resp.Code != http.StatusOK || actual.Code != goerror.CodeInternalServerError {
+		t.Error("Error", resp.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/fingerprints?fingerprint=unknown", nil)
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusNotFound {
+		t.Error("Error", resp.Code)
+	}
+}
+
+func TestFingerprintsLimit(t *testing.T) {
+	fingerprints := echoerror.NewFingerprints(2)
+	now := time.Now()
+	fingerprints.Record(echoerror.Event{Code: "A", Time: now})
+	fingerprints.Record(echoerror.Event{Code: "B", Time: now.Add(time.Second)})
+	fingerprints.Record(echoerror.Event{Code: "C", Time: now.Add(2 * time.Second)})
+
+	stats := fingerprints.List()
+	if len(stats) != 2 {
+		t.Fatal("Error", stats)
+	}
+	for _, s := range stats {
+		if s.Code == "A" {
+			t.Error("Error", stats)
+		}
+	}
+}
+
+func TestFirstOccurrence(t *testing.T) {
+	emitted := []echoerror.Event{}
+	observer := echoerror.FirstOccurrence(time.Minute, echoerror.ObserverFunc(func(c echo.Context, e echoerror.Event) {
+		emitted = append(emitted, e)
+	}))
+
+	now := time.Now()
+	for _, e := range []echoerror.Event{
+		{Code: "A", Time: now},
+		{Code: "A", Time: now.Add(time.Second)},
+		{Code: "B", Time: now.Add(2 * time.Second)},
+		{Code: "A", Time: now.Add(2 * time.Minute)},
+	} {
+		observer.Observe(nil, e)
+	}
+
+	if len(emitted) != 3 || emitted[0].Code != "A" || emitted[1].Code != "B" || emitted[2].Code != "A" {
+		t.Error("Error", emitted)
+	}
+}
@@ -0,0 +1,65 @@
+package echoerror
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/goerror"
+	"net/http"
+)
+
+// GraphQLResponse is a GraphQL response carrying only errors.
+type GraphQLResponse struct {
+	Errors []GraphQLError `json:"errors"`
+}
+
+// GraphQLError is an entry of the GraphQL "errors" list.
+type GraphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// GraphQLPath is implemented by errors that know the response field they
+// were raised for.
+type GraphQLPath interface {
+	Path() []any
+}
+
+type graphQLRenderer struct {
+}
+
+// Render implements Renderer. The response status is always 200 OK, the
+// error status is reported in extensions.
+func (g *graphQLRenderer) Render(c echo.Context, code int, err error) error {
+	body, _ := goerror.GetBody(err)
+	message := body.Message
+	if message == "" {
+		message = err.Error()
+	}
+
+	entry := GraphQLError{
+		Message: message,
+		Extensions: map[string]any{
+			"code":   body.Code,
+			"status": code,
+		},
+	}
+	if p, ok := err.(GraphQLPath); ok {
+		entry.Path = p.Path()
+	}
+	if fe, ok := err.(FieldErrors); ok && len(fe.FieldErrors()) > 0 {
+		entry.Extensions["fields"] = fe.FieldErrors()
+	}
+
+	return c.JSON(http.StatusOK, GraphQLResponse{Errors: []GraphQLError{entry}})
+}
+
+// Status implements StatusRenderer.
+func (g *graphQLRenderer) Status(c echo.Context, code int) int {
+	return http.StatusOK
+}
+
+// NewGraphQLRenderer returns a Renderer that writes errors as a GraphQL
+// errors envelope.
+func NewGraphQLRenderer() Renderer {
+	return &graphQLRenderer{}
+}
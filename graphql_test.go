@@ -0,0 +1,151 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type ResolverError struct {
+	goerror.Body
+	FieldPath []any
+}
+
+// Error implements error.
+func (r *ResolverError) Error() string {
+	return r.Message
+}
+
+// Path implements echoerror.GraphQLPath.
+func (r *ResolverError) Path() []any {
+	return r.FieldPath
+}
+
+func TestGraphQLRenderer(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Renderer: echoerror.NewGraphQLRenderer(),
+	})
+
+	handler := func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewUnauthorized())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = handler(c)
+
+	if resp.Code != http.StatusOK {
+		t.Error("Error", resp.Code)
+	}
+	actual := echoerror.GraphQLResponse{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if len(actual.Errors) != 1 ||
+		actual.Errors[0].Message != "Unauthorized" ||
+		actual.Errors[0].Extensions["code"] != goerror.CodeUnauthorized ||
+		actual.Errors[0].Extensions["status"] != float64(http.StatusUnauthorized) {
+		t.Error("Error", resp.Body.String())
+	}
+}
+
+func TestGraphQLRendererPerRoute(t *testing.T) {
+	app := echo.New()
+	customResp := echoerror.Custom(&resolverResponse{})
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+	})
+
+	app.POST("/graphql", func(c echo.Context) error {
+		return res.With(c).Response(&ResolverError{
+			Body:      goerror.Body{Code: "GQL001", Message: "User not found"},
+			FieldPath: []any{"user", 0, "name"},
+		})
+	}, echoerror.UseRenderer(echoerror.NewGraphQLRenderer()))
+	app.POST("/rest", func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewNotFound())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Error("Error", resp.Code)
+	}
+	actual := echoerror.GraphQLResponse{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if len(actual.Errors) != 1 || len(actual.Errors[0].Path) != 3 || actual.Errors[0].Extensions["status"] != float64(http.StatusNotFound) {
+		t.Error("Error", resp.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/rest", nil)
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusNotFound {
+		t.Error("Error", resp.Code)
+	}
+}
+
+func TestGraphQLRendererBodiless(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Renderer: echoerror.NewVersionedRenderer(echoerror.VersionConfig{
+			Formats: []echoerror.Format{{Version: "1", Renderer: echoerror.NewGraphQLRenderer()}},
+		}),
+	})
+	app.Match([]string{http.MethodGet, http.MethodHead}, "/graphql", func(c echo.Context) error {
+		switch c.QueryParam("error") {
+		case "no-content":
+			return res.With(c).Response(goerror.NewNoContent())
+		case "not-modified":
+			return res.With(c).Response(goerror.NewNotModified())
+		}
+		return res.With(c).Response(goerror.NewNotFound())
+	})
+
+	cases := []struct {
+		method string
+		target string
+		status float64
+	}{
+		{http.MethodGet, "/graphql?error=no-content", http.StatusNoContent},
+		{http.MethodGet, "/graphql?error=not-modified", http.StatusNotModified},
+		{http.MethodHead, "/graphql", 0},
+	}
+	for _, tc := range cases {
+		resp := httptest.NewRecorder()
+		app.ServeHTTP(resp, httptest.NewRequest(tc.method, tc.target, nil))
+
+		if resp.Code != http.StatusOK {
+			t.Error("Error", tc.target, resp.Code)
+		}
+		actual := echoerror.GraphQLResponse{}
+		_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+		if tc.status == 0 {
+			if resp.Body.Len() != 0 {
+				t.Error("Error", tc.method, resp.Body.String())
+			}
+		} else if len(actual.Errors) != 1 || actual.Errors[0].Extensions["status"] != tc.status {
+			t.Error("Error", tc.target, resp.Body.String())
+		}
+	}
+}
+
+type resolverResponse struct {
+}
+
+// Response implements response.Custom.
+func (r *resolverResponse) Response(ctx echo.Context, err error) error {
+	switch e := err.(type) {
+	case *ResolverError:
+		return ctx.JSON(http.StatusNotFound, e)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package echoerror
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// DefaultHeaders returns the headers set on 4xx and 5xx errors when
+// Config.Headers is nil. They keep shared caches from storing error bodies,
+// e.g. of a 401, and browsers from sniffing or framing them. 2xx and 3xx
+// responses, e.g. redirects, are left cacheable.
+func DefaultHeaders() map[string]string {
+	return map[string]string{
+		echo.HeaderCacheControl:        "no-store",
+		echo.HeaderXContentTypeOptions: "nosniff",
+		echo.HeaderXFrameOptions:       "DENY",
+	}
+}
+
+// setHeaders sets the configured headers for the status code of the response
+// right before it is written, whichever way the error is rendered.
+func (s *httpResponse) setHeaders() {
+	res := s.Ctx.Response()
+	res.Before(func() {
+		h := res.Header()
+		for name, value := range s.headers(res.Status) {
+			if value == "" {
+				h.Del(name)
+			} else {
+				h.Set(name, value)
+			}
+		}
+	})
+}
+
+// headers returns Headers, over DefaultHeaders for errors when the global
+// Headers are nil, with the ClassHeaders of the status class of code applied.
+func (s *httpResponse) headers(code int) map[string]string {
+	var base map[string]string
+	if s.defaultHeaders && code >= http.StatusBadRequest {
+		base = DefaultHeaders()
+	}
+	return mergeHeaders(mergeHeaders(base, s.Headers), s.ClassHeaders[code/100])
+}
+
+// mergeHeaders returns a copy of base with the values of o applied.
+func mergeHeaders(base, o map[string]string) map[string]string {
+	m := make(map[string]string, len(base)+len(o))
+	for name, value := range base {
+		m[name] = value
+	}
+	for name, value := range o {
+		m[name] = value
+	}
+	return m
+}
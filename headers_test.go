@@ -0,0 +1,159 @@
+package echoerror_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDefaultHeaders(t *testing.T) {
+	app := echo.New()
+	customResp := echoerror.Custom(&validationResponse{})
+	res := echoerror.New(&echoerror.Config{Custom: &customResp})
+
+	for _, err := range []error{goerror.NewUnauthorized(), &ValidationError{Body: goerror.Body{Code: "VAL001"}}} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := app.NewContext(req, resp)
+		c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=3600")
+
+		_ = res.With(c).Response(err)
+
+		h := resp.Header()
+		if h.Get(echo.HeaderCacheControl) != "no-store" || h.Get(echo.HeaderXContentTypeOptions) != "nosniff" || h.Get(echo.HeaderXFrameOptions) != "DENY" {
+			t.Error("Error", err, h)
+		}
+	}
+}
+
+func TestDefaultHeadersErrorsOnly(t *testing.T) {
+	app := echo.New()
+	app.Use(echoerror.Use(echoerror.New()))
+	app.GET("/ok", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderCacheControl, "max-age=60")
+		return echoerror.Respond(c, goerror.NewOK(nil))
+	})
+	app.GET("/moved", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderCacheControl, "max-age=60")
+		return echoerror.Respond(c, goerror.NewMovedPermanently())
+	})
+	admin := app.Group("/admin", echoerror.Override(&echoerror.Config{
+		Headers: map[string]string{"X-Extra": "1"},
+	}))
+	admin.GET("/ok", func(c echo.Context) error {
+		return echoerror.Respond(c, goerror.NewOK(nil))
+	})
+	admin.GET("/forbidden", func(c echo.Context) error {
+		return echoerror.Respond(c, goerror.NewForbidden())
+	})
+
+	for _, target := range []string{"/ok", "/moved"} {
+		resp := httptest.NewRecorder()
+		app.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+		h := resp.Header()
+		if h.Get(echo.HeaderCacheControl) != "max-age=60" || h.Get(echo.HeaderXContentTypeOptions) != "" || h.Get(echo.HeaderXFrameOptions) != "" {
+			t.Error("Error", target, h)
+		}
+	}
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin/ok", nil))
+	if h := resp.Header(); h.Get("X-Extra") != "1" || h.Get(echo.HeaderCacheControl) != "" || h.Get(echo.HeaderXFrameOptions) != "" {
+		t.Error("Error", h)
+	}
+
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin/forbidden", nil))
+	if h := resp.Header(); h.Get("X-Extra") != "1" || h.Get(echo.HeaderCacheControl) != "no-store" || h.Get(echo.HeaderXFrameOptions) != "DENY" {
+		t.Error("Error", h)
+	}
+}
+
+func TestClassHeaders(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Headers: map[string]string{
+			echo.HeaderCacheControl:        "no-store",
+			echo.HeaderXContentTypeOptions: "nosniff",
+		},
+		ClassHeaders: map[int]map[string]string{
+			3: {echo.HeaderCacheControl: ""},
+			5: {echo.HeaderRetryAfter: "30"},
+		},
+	})
+	app.Use(echoerror.Use(res))
+	app.GET("/moved", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderCacheControl, "max-age=60")
+		return echoerror.Respond(c, goerror.NewMovedPermanently())
+	})
+	app.GET("/unavailable", func(c echo.Context) error {
+		return echoerror.Respond(c, goerror.NewServiceUnavailable())
+	})
+	admin := app.Group("/admin", echoerror.Override(&echoerror.Config{
+		Headers: map[string]string{echo.HeaderXContentTypeOptions: ""},
+	}))
+	admin.GET("", func(c echo.Context) error {
+		return echoerror.Respond(c, goerror.NewForbidden())
+	})
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/moved", nil))
+	if _, ok := resp.Header()[echo.HeaderCacheControl]; ok || resp.Header().Get(echo.HeaderXContentTypeOptions) != "nosniff" {
+		t.Error("Error", resp.Header())
+	}
+
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/unavailable", nil))
+	if resp.Header().Get(echo.HeaderRetryAfter) != "30" || resp.Header().Get(echo.HeaderCacheControl) != "no-store" || resp.Header().Get(echo.HeaderXFrameOptions) != "" {
+		t.Error("Error", resp.Header())
+	}
+
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	if resp.Code != http.StatusForbidden || resp.Header().Get(echo.HeaderCacheControl) != "no-store" || resp.Header().Get(echo.HeaderXContentTypeOptions) != "" {
+		t.Error("Error", resp.Code, resp.Header())
+	}
+}
+
+func TestNestedOverrideHeaders(t *testing.T) {
+	cases := []struct {
+		global   map[string]string
+		expected map[string]string
+	}{
+		{
+			global:   map[string]string{echo.HeaderCacheControl: "private"},
+			expected: map[string]string{echo.HeaderCacheControl: "private", "X-Extra": "1", echo.HeaderXContentTypeOptions: "", echo.HeaderXFrameOptions: ""},
+		},
+		{
+			global:   map[string]string{},
+			expected: map[string]string{echo.HeaderCacheControl: "", "X-Extra": "1", echo.HeaderXContentTypeOptions: ""},
+		},
+		{
+			global:   nil,
+			expected: map[string]string{echo.HeaderCacheControl: "no-store", "X-Extra": "1", echo.HeaderXContentTypeOptions: "nosniff", echo.HeaderXFrameOptions: ""},
+		},
+	}
+	for _, tc := range cases {
+		app := echo.New()
+		app.Use(echoerror.Use(echoerror.New(&echoerror.Config{Headers: tc.global})))
+		outer := app.Group("/api", echoerror.Override(&echoerror.Config{AbortOnCommitted: true}))
+		inner := outer.Group("/admin", echoerror.Override(&echoerror.Config{
+			Headers: map[string]string{"X-Extra": "1", echo.HeaderXFrameOptions: ""},
+		}))
+		inner.GET("", func(c echo.Context) error {
+			return echoerror.Respond(c, goerror.NewUnauthorized())
+		})
+
+		resp := httptest.NewRecorder()
+		app.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/admin", nil))
+
+		for name, value := range tc.expected {
+			if resp.Header().Get(name) != value {
+				t.Error("Error", tc.global, name, resp.Header())
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package echoerror
+
+import (
+	"bytes"
+	"embed"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/goerror"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//go:embed templates/error.html
+var defaultTemplates embed.FS
+
+// HTMLData is the data of an HTML error page.
+type HTMLData struct {
+	Status int
+	// Title is the status text, e.g. "Not Found".
+	Title string
+	Code  string
+	// Message is the message of the error, localized when I18n is enabled.
+	Message   string
+	RequestID string
+	// Lang is the Content-Language of a localized message.
+	Lang   string
+	Fields map[string]string
+	Body   goerror.Body
+}
+
+type HTMLConfig struct {
+	// Templates holds the pages by status code or class, e.g. "404.html" and
+	// "5xx.html", with "error.html" for any other status. Pages it lacks are
+	// taken from the embedded default set.
+	Templates *template.Template
+	// Fallback renders requests that do not prefer HTML. Errors are written as
+	// plain JSON when nil.
+	Fallback Renderer
+}
+
+type htmlRenderer struct {
+	config   HTMLConfig
+	defaults *template.Template
+}
+
+// Render implements Renderer.
+func (h *htmlRenderer) Render(c echo.Context, code int, err error) error {
+	addVary(c.Response().Header(), echo.HeaderAccept)
+	if !AcceptsHTML(c.Request().Header.Get(echo.HeaderAccept)) {
+		if h.config.Fallback != nil {
+			return h.config.Fallback.Render(c, code, err)
+		}
+		return c.JSON(code, err)
+	}
+
+	body, _ := goerror.GetBody(err)
+	data := HTMLData{
+		Status:    code,
+		Title:     http.StatusText(code),
+		Code:      body.Code,
+		Message:   body.Message,
+		RequestID: RequestID(c),
+		Lang:      c.Response().Header().Get(HeaderContentLanguage),
+		Body:      body,
+	}
+	if data.Message == "" {
+		data.Message = err.Error()
+	}
+	if fe, ok := err.(FieldErrors); ok {
+		data.Fields = fe.FieldErrors()
+	}
+
+	var buf bytes.Buffer
+	if err := h.page(code).Execute(&buf, data); err != nil {
+		return err
+	}
+	return c.HTMLBlob(code, buf.Bytes())
+}
+
+// page returns the template of the status code, its class or "error.html",
+// preferring the configured templates over the default set.
+func (h *htmlRenderer) page(code int) *template.Template {
+	names := []string{
+		strconv.Itoa(code) + ".html",
+		strconv.Itoa(code/100) + "xx.html",
+		"error.html",
+	}
+	for _, set := range []*template.Template{h.config.Templates, h.defaults} {
+		if set == nil {
+			continue
+		}
+		for _, name := range names {
+			if t := set.Lookup(name); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// AcceptsHTML reports whether an Accept header prefers HTML over JSON, as
+// browsers do. Wildcards alone do not select HTML.
+func AcceptsHTML(accept string) bool {
+	html, json := 0.0, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(fields[0])) {
+		case echo.MIMETextHTML, "application/xhtml+xml":
+			html = max(html, q)
+		case echo.MIMEApplicationJSON:
+			json = max(json, q)
+		}
+	}
+	return html > 0 && html >= json
+}
+
+// NewHTMLRenderer returns a Renderer that writes error pages with html/template
+// to requests preferring HTML, and renders other requests with
+// config.Fallback. It adds Accept to the Vary header.
+func NewHTMLRenderer(config HTMLConfig) Renderer {
+	return &htmlRenderer{
+		config:   config,
+		defaults: template.Must(template.ParseFS(defaultTemplates, "templates/*.html")),
+	}
+}
@@ -0,0 +1,110 @@
+package echoerror_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+func TestHTMLRenderer(t *testing.T) {
+	app := echo.New()
+	customResp := echoerror.Custom(&validationResponse{})
+	res := echoerror.New(&echoerror.Config{
+		Custom:   &customResp,
+		Renderer: echoerror.NewHTMLRenderer(echoerror.HTMLConfig{}),
+	})
+	app.GET("/", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderXRequestID, "req-1")
+		return res.With(c).Response(&ValidationError{
+			Body:   goerror.Body{Code: "VAL001", Message: "Invalid <input>"},
+			Fields: map[string]string{"email": "is invalid"},
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAccept, browserAccept)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	body := resp.Body.String()
+	if resp.Code != http.StatusUnprocessableEntity || resp.Header().Get(echo.HeaderContentType) != echo.MIMETextHTMLCharsetUTF8 {
+		t.Error("Error", resp.Code, resp.Header())
+	}
+	for _, s := range []string{"<title>422 Unprocessable Entity</title>", "Invalid &lt;input&gt;", "<strong>email</strong>: is invalid", "Code VAL001", "Request req-1"} {
+		if !strings.Contains(body, s) {
+			t.Error("Error", s, body)
+		}
+	}
+	if resp.Header().Get(echo.HeaderVary) != echo.HeaderAccept {
+		t.Error("Error", resp.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAccept, "*/*")
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if !strings.HasPrefix(resp.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) || !strings.Contains(resp.Body.String(), `"code":"VAL001"`) {
+		t.Error("Error", resp.Header(), resp.Body.String())
+	}
+}
+
+func TestHTMLRendererTemplates(t *testing.T) {
+	pages := template.Must(template.New("404.html").Parse(`<p lang="{{.Lang}}">{{.Message}} not found</p>`))
+	template.Must(pages.New("4xx.html").Parse(`<p>client error {{.Status}}</p>`))
+
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Renderer: echoerror.NewHTMLRenderer(echoerror.HTMLConfig{
+			Templates: pages,
+			Fallback:  echoerror.NewProblemRenderer(""),
+		}),
+	})
+
+	cases := []struct {
+		err      error
+		accept   string
+		expected string
+	}{
+		{goerror.NewNotFound(), "text/html", `<p lang="">Not Found not found</p>`},
+		{goerror.NewConflict(), "text/html, application/json", `<p>client error 409</p>`},
+		{goerror.NewBadGateway(), "text/html", `<title>502 Bad Gateway</title>`},
+		{goerror.NewNotFound(), "application/json, text/html;q=0.5", `"type":"about:blank"`},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAccept, tc.accept)
+		resp := httptest.NewRecorder()
+		c := app.NewContext(req, resp)
+
+		_ = res.With(c).Response(tc.err)
+
+		if !strings.Contains(resp.Body.String(), tc.expected) {
+			t.Error("Error", tc.accept, resp.Body.String())
+		}
+	}
+}
+
+func TestAcceptsHTML(t *testing.T) {
+	cases := map[string]bool{
+		browserAccept:                       true,
+		"":                                  false,
+		"*/*":                               false,
+		"application/json":                  false,
+		"text/html;q=0.4, application/json": false,
+		"text/html, application/json":       true,
+		"text/html;q=0":                     false,
+	}
+	for accept, expected := range cases {
+		if echoerror.AcceptsHTML(accept) != expected {
+			t.Error("Error", accept)
+		}
+	}
+}
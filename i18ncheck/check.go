@@ -0,0 +1,132 @@
+package i18ncheck
+
+import (
+	"fmt"
+	"github.com/prongbang/echoerror"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	actionPattern = regexp.MustCompile(`\{\{(.*?)\}\}`)
+	fieldPattern  = regexp.MustCompile(`\.([A-Za-z_]\w*)`)
+)
+
+// Issue kinds reported by Check.
+const (
+	IssueMissing     = "missing"
+	IssueUnused      = "unused"
+	IssuePlaceholder = "placeholder"
+)
+
+// Issue is a problem of a translation key in a locale.
+type Issue struct {
+	Kind   string
+	Locale string
+	Code   string
+	Detail string
+}
+
+func (i Issue) String() string {
+	s := fmt.Sprintf("%s: %s %s", i.Locale, i.Kind, i.Code)
+	if i.Detail != "" {
+		s += " (" + i.Detail + ")"
+	}
+	return s
+}
+
+// Options tune Check.
+type Options struct {
+	// DefaultLocale is the locale other locales are compared with.
+	DefaultLocale string
+	// IgnoreUnused skips messages no source uses.
+	IgnoreUnused bool
+	// AllowFallback accepts a code missing in a locale when one of its
+	// fallbacks, e.g. "th" for "th-TH" or the default locale, has it.
+	AllowFallback bool
+}
+
+// Check compares the codes used in source with the messages of every locale
+// of localizer. A code is missing in a locale when the locale has no message
+// for it, and unused when no source uses it. Messages must use the
+// placeholders of the default locale.
+func Check(localizer *echoerror.Localizer, usages []Usage, options Options) []Issue {
+	defaultLocale := echoerror.NormalizeLocale(options.DefaultLocale)
+	used := map[string][]Usage{}
+	for _, u := range usages {
+		used[u.Code] = append(used[u.Code], u)
+	}
+	codes := make([]string, 0, len(used))
+	for code := range used {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	defaults := localizer.Messages(defaultLocale)
+	var issues []Issue
+	for _, locale := range localizer.Locales() {
+		messages := localizer.Messages(locale)
+		for _, code := range codes {
+			if _, ok := messages[code]; ok {
+				continue
+			}
+			if _, ok := localizer.Message(locale, code); !ok || !options.AllowFallback {
+				u := used[code][0]
+				issues = append(issues, Issue{Kind: IssueMissing, Locale: locale, Code: code, Detail: u.Pos.String()})
+			}
+		}
+
+		keys := make([]string, 0, len(messages))
+		for code := range messages {
+			keys = append(keys, code)
+		}
+		sort.Strings(keys)
+		for _, code := range keys {
+			if _, ok := used[code]; !ok && !options.IgnoreUnused {
+				issues = append(issues, Issue{Kind: IssueUnused, Locale: locale, Code: code})
+			}
+			forms, ok := defaults[code]
+			if !ok || locale == defaultLocale {
+				continue
+			}
+			expected, actual := placeholders(forms), placeholders(messages[code])
+			if expected != actual {
+				issues = append(issues, Issue{
+					Kind:   IssuePlaceholder,
+					Locale: locale,
+					Code:   code,
+					Detail: fmt.Sprintf("want [%s], got [%s]", expected, actual),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// placeholders returns the sorted template fields used by the plural forms of
+// a message.
+func placeholders(forms map[string]string) string {
+	set := map[string]bool{}
+	for _, message := range forms {
+		for _, action := range actionPattern.FindAllStringSubmatch(message, -1) {
+			for _, field := range fieldPattern.FindAllStringSubmatch(action[1], -1) {
+				set[field[1]] = true
+			}
+		}
+	}
+	fields := make([]string, 0, len(set))
+	for field := range set {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, " ")
+}
+
+// Report writes the issues to w, one per line.
+func Report(w io.Writer, issues []Issue) {
+	for _, issue := range issues {
+		fmt.Fprintln(w, issue)
+	}
+}
@@ -0,0 +1,143 @@
+package i18ncheck
+
+import (
+	"encoding/json"
+	"github.com/prongbang/echoerror"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const source = `package app
+
+import errs "github.com/prongbang/goerror"
+
+const codeQuota = "QUO001"
+
+func handlers() []error {
+	return []error{
+		&errs.Body{Code: "CUS001"},
+		&errs.Body{Code: codeQuota},
+		&errs.Body{Code: errs.CodeConflict},
+		errs.NewNotFound(),
+	}
+}
+`
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("app/app.go", source)
+	write("app/app_test.go", "package app\n\nimport \"github.com/prongbang/goerror\"\n\nvar _ = goerror.Body{Code: \"TST001\"}\n")
+	write("vendor/lib/lib.go", "package lib\n\nimport \"github.com/prongbang/goerror\"\n\nvar _ = goerror.Body{Code: \"VEN001\"}\n")
+
+	usages, err := Scan(dir)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	var codes []string
+	for _, u := range usages {
+		codes = append(codes, u.Code)
+	}
+	if strings.Join(codes, ",") != "CLE004,CLE009,CUS001,QUO001" {
+		t.Error("Error", codes)
+	}
+
+	localizer, err := echoerror.NewLocalizer(echoerror.LocalizerConfig{
+		FS: fstest.MapFS{
+			"en.json": {Data: []byte(`{"CUS001": "Custom", "CLE004": "Not found", "CLE009": "Conflict", "QUO001": {"one": "{{.count}} request left", "other": "{{.count}} requests left"}}`)},
+			"th.json": {Data: []byte(`{"CUS001": "กำหนดเอง", "QUO001": "เหลือ {{.remaining}} ครั้ง", "OLD001": "เก่า"}`)},
+			"ja.json": {Data: []byte(`{"CUS001": "カスタム"}`)},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	issues := func(options Options) string {
+		var actual []string
+		for _, issue := range Check(localizer, usages, options) {
+			actual = append(actual, issue.Locale+" "+issue.Kind+" "+issue.Code)
+		}
+		return strings.Join(actual, "\n")
+	}
+	expected := []string{
+		"ja missing CLE004",
+		"ja missing CLE009",
+		"ja missing QUO001",
+		"th missing CLE004",
+		"th missing CLE009",
+		"th unused OLD001",
+		"th placeholder QUO001",
+	}
+	if actual := issues(Options{DefaultLocale: "en"}); actual != strings.Join(expected, "\n") {
+		t.Error("Error", actual)
+	}
+	expected = []string{
+		"th placeholder QUO001",
+	}
+	if actual := issues(Options{DefaultLocale: "en", IgnoreUnused: true, AllowFallback: true}); actual != strings.Join(expected, "\n") {
+		t.Error("Error", actual)
+	}
+
+	localizer, _ = echoerror.NewLocalizer(echoerror.LocalizerConfig{
+		FS: fstest.MapFS{
+			"en.json": {Data: []byte(`{"CUS001": "Custom"}`)},
+		},
+	})
+	missing := Check(localizer, usages, Options{DefaultLocale: "en", IgnoreUnused: true})
+	if len(missing) != 3 || missing[0].Kind != IssueMissing || missing[0].Code != "CLE004" || !strings.Contains(missing[0].Detail, "app.go:12") {
+		t.Error("Error", missing)
+	}
+
+	write("i18n/en.json", `{}`)
+	write("i18n/th.yaml", "CUS001: กำหนดเอง\n")
+	if files, err := undecodable(filepath.Join(dir, "i18n"), nil); err != nil || len(files) != 1 || files[0] != "th.yaml" {
+		t.Error("Error", files, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.go"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	i18n := filepath.Join(dir, "i18n")
+	if err := os.Mkdir(i18n, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(i18n, "en.yaml"), []byte(`{"CUS001": "Custom", "CLE004": "Not found", "CLE009": "Conflict", "QUO001": "Quota"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-src", dir, "-dir", i18n}
+
+	var stdout, stderr strings.Builder
+	if status := Run(args, &stdout, &stderr, nil); status != 2 || !strings.Contains(stderr.String(), "en.yaml") {
+		t.Error("Error", status, stderr.String())
+	}
+
+	// A JSON subset stands in for a YAML decoder.
+	stdout.Reset()
+	stderr.Reset()
+	decoders := map[string]echoerror.Decoder{".yaml": json.Unmarshal}
+	if status := Run(args, &stdout, &stderr, decoders); status != 0 || stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Error("Error", status, stdout.String(), stderr.String())
+	}
+
+	if err := os.WriteFile(filepath.Join(i18n, "th.yaml"), []byte(`{"CUS001": "กำหนดเอง"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if status := Run(args, &stdout, &stderr, decoders); status != 1 || !strings.Contains(stdout.String(), "th: missing CLE004") {
+		t.Error("Error", status, stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,73 @@
+package i18ncheck
+
+import (
+	"github.com/prongbang/goerror"
+)
+
+// goerrorCodes maps the names of goerror types to their code constants, so
+// both goerror.NewNotFound() and goerror.CodeNotFound resolve to
+// goerror.CodeNotFound.
+var goerrorCodes = map[string]string{
+	"Continue":                      goerror.CodeContinue,
+	"SwitchingProtocols":            goerror.CodeSwitchingProtocols,
+	"Processing":                    goerror.CodeProcessing,
+	"EarlyHints":                    goerror.CodeEarlyHints,
+	"OK":                            goerror.CodeOK,
+	"Created":                       goerror.CodeCreated,
+	"Accepted":                      goerror.CodeAccepted,
+	"NonAuthoritativeInformation":   goerror.CodeNonAuthoritativeInformation,
+	"NoContent":                     goerror.CodeNoContent,
+	"ResetContent":                  goerror.CodeResetContent,
+	"PartialContent":                goerror.CodePartialContent,
+	"MultiStatus":                   goerror.CodeMultiStatus,
+	"AlreadyReported":               goerror.CodeAlreadyReported,
+	"IMUsed":                        goerror.CodeIMUsed,
+	"MultipleChoices":               goerror.CodeMultipleChoices,
+	"MovedPermanently":              goerror.CodeMovedPermanently,
+	"Found":                         goerror.CodeFound,
+	"SeeOther":                      goerror.CodeSeeOther,
+	"NotModified":                   goerror.CodeNotModified,
+	"UseProxy":                      goerror.CodeUseProxy,
+	"TemporaryRedirect":             goerror.CodeTemporaryRedirect,
+	"PermanentRedirect":             goerror.CodePermanentRedirect,
+	"BadRequest":                    goerror.CodeBadRequest,
+	"Unauthorized":                  goerror.CodeUnauthorized,
+	"PaymentRequired":               goerror.CodePaymentRequired,
+	"Forbidden":                     goerror.CodeForbidden,
+	"NotFound":                      goerror.CodeNotFound,
+	"MethodNotAllowed":              goerror.CodeMethodNotAllowed,
+	"NotAcceptable":                 goerror.CodeNotAcceptable,
+	"ProxyAuthRequired":             goerror.CodeProxyAuthRequired,
+	"RequestTimeout":                goerror.CodeRequestTimeout,
+	"Conflict":                      goerror.CodeConflict,
+	"Gone":                          goerror.CodeGone,
+	"LengthRequired":                goerror.CodeLengthRequired,
+	"PreconditionFailed":            goerror.CodePreconditionFailed,
+	"RequestEntityTooLarge":         goerror.CodeRequestEntityTooLarge,
+	"RequestURITooLong":             goerror.CodeRequestURITooLong,
+	"UnsupportedMediaType":          goerror.CodeUnsupportedMediaType,
+	"RequestedRangeNotSatisfiable":  goerror.CodeRequestedRangeNotSatisfiable,
+	"ExpectationFailed":             goerror.CodeExpectationFailed,
+	"Teapot":                        goerror.CodeTeapot,
+	"MisdirectedRequest":            goerror.CodeMisdirectedRequest,
+	"UnprocessableEntity":           goerror.CodeUnprocessableEntity,
+	"Locked":                        goerror.CodeLocked,
+	"FailedDependency":              goerror.CodeFailedDependency,
+	"TooEarly":                      goerror.CodeTooEarly,
+	"UpgradeRequired":               goerror.CodeUpgradeRequired,
+	"PreconditionRequired":          goerror.CodePreconditionRequired,
+	"TooManyRequests":               goerror.CodeTooManyRequests,
+	"RequestHeaderFieldsTooLarge":   goerror.CodeRequestHeaderFieldsTooLarge,
+	"UnavailableForLegalReasons":    goerror.CodeUnavailableForLegalReasons,
+	"InternalServerError":           goerror.CodeInternalServerError,
+	"NotImplemented":                goerror.CodeNotImplemented,
+	"BadGateway":                    goerror.CodeBadGateway,
+	"ServiceUnavailable":            goerror.CodeServiceUnavailable,
+	"GatewayTimeout":                goerror.CodeGatewayTimeout,
+	"HTTPVersionNotSupported":       goerror.CodeHTTPVersionNotSupported,
+	"VariantAlsoNegotiates":         goerror.CodeVariantAlsoNegotiates,
+	"InsufficientStorage":           goerror.CodeInsufficientStorage,
+	"LoopDetected":                  goerror.CodeLoopDetected,
+	"NotExtended":                   goerror.CodeNotExtended,
+	"NetworkAuthenticationRequired": goerror.CodeNetworkAuthenticationRequired,
+}
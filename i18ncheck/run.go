@@ -0,0 +1,98 @@
+// Package i18ncheck checks the translation files of an echoerror Localizer
+// against the goerror codes used in Go source.
+//
+// It reports, per locale, the codes used in source without a message, the
+// messages no source uses and the messages whose placeholders differ from the
+// default locale. JSON translation files are decoded out of the box; other
+// formats need a Decoder, e.g. a command of its own:
+//
+//	func main() {
+//		os.Exit(i18ncheck.Run(os.Args[1:], os.Stdout, os.Stderr, map[string]echoerror.Decoder{
+//			".yaml": yaml.Unmarshal,
+//		}))
+//	}
+package i18ncheck
+
+import (
+	"flag"
+	"fmt"
+	"github.com/prongbang/echoerror"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Run runs the check with the command line args and returns the exit status:
+// 1 when there is any issue and 2 when the check cannot run, e.g. the
+// directory holds YAML or TOML files and decoders has none for them, as they
+// would be skipped silently. Issues are written to stdout and errors to
+// stderr.
+func Run(args []string, stdout, stderr io.Writer, decoders map[string]echoerror.Decoder) int {
+	flags := flag.NewFlagSet("echoerror-i18n", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	src := flags.String("src", ".", "directory of the Go source to scan")
+	dir := flags.String("dir", "i18n", "directory of the translation files")
+	defaultLocale := flags.String("default-locale", "en", "locale other locales fall back to and are compared with")
+	ignoreUnused := flags.Bool("ignore-unused", false, "do not report messages unused in source")
+	allowFallback := flags.Bool("allow-fallback", false, "accept codes missing in a locale but found in its fallbacks")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	fatal := func(err error) int {
+		fmt.Fprintln(stderr, "echoerror-i18n:", err)
+		return 2
+	}
+
+	files, err := undecodable(*dir, decoders)
+	if err != nil {
+		return fatal(err)
+	}
+	if len(files) > 0 {
+		return fatal(fmt.Errorf("cannot decode %s: no decoder for their format", strings.Join(files, ", ")))
+	}
+
+	usages, err := Scan(*src)
+	if err != nil {
+		return fatal(err)
+	}
+	localizer, err := echoerror.NewLocalizer(echoerror.LocalizerConfig{
+		FS:            os.DirFS(*dir),
+		DefaultLocale: *defaultLocale,
+		Decoders:      decoders,
+	})
+	if err != nil {
+		return fatal(err)
+	}
+
+	issues := Check(localizer, usages, Options{
+		DefaultLocale: *defaultLocale,
+		IgnoreUnused:  *ignoreUnused,
+		AllowFallback: *allowFallback,
+	})
+	Report(stdout, issues)
+	if len(issues) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// undecodable returns the YAML and TOML translation files in dir that have no
+// decoder.
+func undecodable(dir string, decoders map[string]echoerror.Decoder) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		switch ext {
+		case ".yaml", ".yml", ".toml":
+			if _, ok := decoders[ext]; !ok {
+				files = append(files, entry.Name())
+			}
+		}
+	}
+	return files, nil
+}
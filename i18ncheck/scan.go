@@ -0,0 +1,179 @@
+package i18ncheck
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const goerrorPath = "github.com/prongbang/goerror"
+
+// Usage is a place in the source where an error code is used.
+type Usage struct {
+	Code string
+	Pos  token.Position
+}
+
+// Scan returns the error codes used in the Go files under root, from
+// goerror.Body{Code: ...} literals and goerror constructor calls. Test files,
+// vendor and testdata directories are skipped.
+func Scan(root string) ([]Usage, error) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	consts := packageConsts(fset, files)
+	var usages []Usage
+	for _, f := range files {
+		pkg := goerrorName(f)
+		dir := filepath.Dir(fset.Position(f.Pos()).Filename)
+		resolve := func(e ast.Expr) (string, bool) {
+			return resolveCode(e, pkg, consts[dir])
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.CompositeLit:
+				if pkg == "" || !isSelector(x.Type, pkg, "Body") {
+					return true
+				}
+				for _, elt := range x.Elts {
+					kv, ok := elt.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Code" {
+						if code, ok := resolve(kv.Value); ok {
+							usages = append(usages, Usage{Code: code, Pos: fset.Position(kv.Value.Pos())})
+						}
+					}
+				}
+			case *ast.CallExpr:
+				sel, ok := x.Fun.(*ast.SelectorExpr)
+				if !ok || pkg == "" || !isIdent(sel.X, pkg) || !strings.HasPrefix(sel.Sel.Name, "New") {
+					return true
+				}
+				if code, ok := goerrorCodes[strings.TrimPrefix(sel.Sel.Name, "New")]; ok {
+					usages = append(usages, Usage{Code: code, Pos: fset.Position(x.Pos())})
+				}
+			}
+			return true
+		})
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Code != usages[j].Code {
+			return usages[i].Code < usages[j].Code
+		}
+		if usages[i].Pos.Filename != usages[j].Pos.Filename {
+			return usages[i].Pos.Filename < usages[j].Pos.Filename
+		}
+		return usages[i].Pos.Line < usages[j].Pos.Line
+	})
+	return usages, nil
+}
+
+// goerrorName returns the name goerror is imported as in f, or "".
+func goerrorName(f *ast.File) string {
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if path != goerrorPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return "goerror"
+	}
+	return ""
+}
+
+// packageConsts returns the string constants declared in each directory.
+func packageConsts(fset *token.FileSet, files []*ast.File) map[string]map[string]string {
+	consts := map[string]map[string]string{}
+	for _, f := range files {
+		dir := filepath.Dir(fset.Position(f.Pos()).Filename)
+		if consts[dir] == nil {
+			consts[dir] = map[string]string{}
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i >= len(vs.Values) {
+						continue
+					}
+					if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						if v, err := strconv.Unquote(lit.Value); err == nil {
+							consts[dir][name.Name] = v
+						}
+					}
+				}
+			}
+		}
+	}
+	return consts
+}
+
+// resolveCode returns the value of a string literal, a constant of the
+// package or a goerror code constant.
+func resolveCode(e ast.Expr, pkg string, consts map[string]string) (string, bool) {
+	switch x := e.(type) {
+	case *ast.BasicLit:
+		if x.Kind == token.STRING {
+			v, err := strconv.Unquote(x.Value)
+			return v, err == nil
+		}
+	case *ast.Ident:
+		v, ok := consts[x.Name]
+		return v, ok
+	case *ast.SelectorExpr:
+		if isIdent(x.X, pkg) && strings.HasPrefix(x.Sel.Name, "Code") {
+			v, ok := goerrorCodes[strings.TrimPrefix(x.Sel.Name, "Code")]
+			return v, ok
+		}
+	}
+	return "", false
+}
+
+func isSelector(e ast.Expr, pkg, name string) bool {
+	sel, ok := e.(*ast.SelectorExpr)
+	return ok && isIdent(sel.X, pkg) && sel.Sel.Name == name
+}
+
+func isIdent(e ast.Expr, name string) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == name
+}
@@ -0,0 +1,102 @@
+package echoerror
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const MIMEApplicationJSONAPI = "application/vnd.api+json"
+
+// JSONAPIDocument is the top-level document of a JSON:API error response.
+type JSONAPIDocument struct {
+	Errors []JSONAPIError `json:"errors"`
+}
+
+// JSONAPIError is a JSON:API error object.
+type JSONAPIError struct {
+	Status string         `json:"status"`
+	Code   string         `json:"code,omitempty"`
+	Title  string         `json:"title,omitempty"`
+	Detail string         `json:"detail,omitempty"`
+	Source *JSONAPISource `json:"source,omitempty"`
+}
+
+// JSONAPISource points to the part of the request document that caused the error.
+type JSONAPISource struct {
+	Pointer string `json:"pointer,omitempty"`
+}
+
+type jsonAPIRenderer struct {
+	PointerPrefix string
+}
+
+// Render implements Renderer.
+func (j *jsonAPIRenderer) Render(c echo.Context, code int, err error) error {
+	return writeJSON(c, code, MIMEApplicationJSONAPI, j.document(code, err))
+}
+
+// document builds the JSON:API document for err. Errors implementing
+// FieldErrors produce one error object per field with a source pointer.
+func (j *jsonAPIRenderer) document(code int, err error) JSONAPIDocument {
+	body, _ := goerror.GetBody(err)
+	status := strconv.Itoa(code)
+	title := http.StatusText(code)
+
+	if fe, ok := err.(FieldErrors); ok {
+		fields := fe.FieldErrors()
+		if len(fields) > 0 {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			doc := JSONAPIDocument{Errors: make([]JSONAPIError, 0, len(names))}
+			for _, name := range names {
+				doc.Errors = append(doc.Errors, JSONAPIError{
+					Status: status,
+					Code:   body.Code,
+					Title:  title,
+					Detail: fields[name],
+					Source: &JSONAPISource{Pointer: j.pointer(name)},
+				})
+			}
+			return doc
+		}
+	}
+
+	detail := body.Message
+	if detail == "" {
+		detail = err.Error()
+	}
+	return JSONAPIDocument{
+		Errors: []JSONAPIError{{
+			Status: status,
+			Code:   body.Code,
+			Title:  title,
+			Detail: detail,
+		}},
+	}
+}
+
+// pointer returns the JSON Pointer (RFC 6901) of a field.
+func (j *jsonAPIRenderer) pointer(field string) string {
+	if strings.HasPrefix(field, "/") {
+		return field
+	}
+	field = strings.ReplaceAll(field, "~", "~0")
+	field = strings.ReplaceAll(field, "/", "~1")
+	return j.PointerPrefix + "/" + field
+}
+
+// NewJSONAPIRenderer returns a Renderer that writes errors as JSON:API error
+// objects. Field errors point to "/data/attributes/<field>".
+func NewJSONAPIRenderer() Renderer {
+	return &jsonAPIRenderer{
+		PointerPrefix: "/data/attributes",
+	}
+}
@@ -0,0 +1,108 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type ValidationError struct {
+	goerror.Body
+	Fields map[string]string `json:"fields"`
+}
+
+// Error implements error.
+func (v *ValidationError) Error() string {
+	return v.Message
+}
+
+// FieldErrors implements echoerror.FieldErrors.
+func (v *ValidationError) FieldErrors() map[string]string {
+	return v.Fields
+}
+
+type validationResponse struct {
+}
+
+// Response implements response.Custom.
+func (v *validationResponse) Response(ctx echo.Context, err error) error {
+	switch e := err.(type) {
+	case *ValidationError:
+		return ctx.JSON(http.StatusUnprocessableEntity, e)
+	}
+	return nil
+}
+
+func TestJSONAPIRenderer(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Renderer: echoerror.NewJSONAPIRenderer(),
+	})
+
+	handler := func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewNotFound())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = handler(c)
+
+	if resp.Code != http.StatusNotFound {
+		t.Error("Error", resp.Code)
+	}
+	if ct := resp.Header().Get(echo.HeaderContentType); ct != echoerror.MIMEApplicationJSONAPI {
+		t.Error("Error", ct)
+	}
+	doc := echoerror.JSONAPIDocument{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &doc)
+	if len(doc.Errors) != 1 || doc.Errors[0].Status != "404" || doc.Errors[0].Code != goerror.CodeNotFound || doc.Errors[0].Title != "Not Found" {
+		t.Error("Error", resp.Body.String())
+	}
+}
+
+func TestJSONAPIRendererFieldErrors(t *testing.T) {
+	app := echo.New()
+	customResp := echoerror.Custom(&validationResponse{})
+	res := echoerror.New(&echoerror.Config{
+		Custom:   &customResp,
+		Renderer: echoerror.NewJSONAPIRenderer(),
+	})
+
+	handler := func(c echo.Context) error {
+		return res.With(c).Response(&ValidationError{
+			Body: goerror.Body{Code: "VAL001", Message: "Validation failed"},
+			Fields: map[string]string{
+				"password":    "Password too short",
+				"email":       "Invalid email format",
+				"address/zip": "Required",
+			},
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = handler(c)
+
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Error", resp.Code)
+	}
+	doc := echoerror.JSONAPIDocument{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &doc)
+	expected := []string{"/data/attributes/address~1zip", "/data/attributes/email", "/data/attributes/password"}
+	if len(doc.Errors) != len(expected) {
+		t.Fatal("Error", resp.Body.String())
+	}
+	for i, e := range doc.Errors {
+		if e.Source == nil || e.Source.Pointer != expected[i] || e.Status != "422" || e.Code != "VAL001" {
+			t.Error("Error", e)
+		}
+	}
+}
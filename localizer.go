@@ -0,0 +1,418 @@
+package echoerror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	HeaderAcceptLanguage  = "Accept-Language"
+	HeaderContentLanguage = "Content-Language"
+	localeContextKey      = "echoerror.locale"
+)
+
+// ErrMessageNotFound is returned by Localizer when no locale of the fallback
+// chain has a message for a code.
+var ErrMessageNotFound = errors.New("echoerror: message not found")
+
+// Decoder decodes a translation file into v.
+type Decoder func(data []byte, v any) error
+
+type LocalizerConfig struct {
+	// FS holds one translation file per locale, named after the locale, e.g.
+	// "en.json", "th.json" and "th-TH.yaml". Usually an embed.FS.
+	FS fs.FS
+	// Dir is the directory of the translation files in FS. Defaults to ".".
+	Dir string
+	// Decoders decode translation files by extension. ".json" is built in;
+	// add others such as {".yaml": yaml.Unmarshal, ".toml": toml.Unmarshal}.
+	// Plural forms may decode as map[string]any or map[any]any.
+	Decoders map[string]Decoder
+	// DefaultLocale ends every fallback chain. Defaults to "en".
+	DefaultLocale string
+	// CountParam names the parameter selecting the plural form of a message.
+	// Defaults to "count".
+	CountParam string
+	// QueryParam, Cookie and ContextKey name where a requested locale is read
+	// from, in this order, before Accept-Language. Empty names are skipped.
+	QueryParam string
+	Cookie     string
+	ContextKey string
+	// OnReload is called after every Reload with its error, e.g. to purge a
+	// LocalizeCache or log a rejected file.
+	OnReload func(err error)
+}
+
+// Localizer loads translation files and localizes error codes in the locale
+// negotiated for the request, falling back from th-TH to th to the default
+// locale.
+//
+// A message is either a string or its plural forms by CLDR category, selected
+// by the count parameter:
+//
+//	{"ITEMS_FAILED": {"one": "{{.count}} item failed", "other": "{{.count}} items failed"}}
+type Localizer struct {
+	config LocalizerConfig
+	// bundles is swapped as a whole by Reload, so lookups never block.
+	bundles atomic.Pointer[bundles]
+	// reload serializes Reload.
+	reload sync.Mutex
+	// digest identifies the translation files last loaded, see Watch.
+	digest string
+}
+
+// bundles holds the messages of every locale by code.
+type bundles map[string]map[string]messageForms
+
+// messageForms holds a message by plural category. A plain message only has
+// the "other" form.
+type messageForms map[string]string
+
+// form returns the message for the plural category of count in locale.
+func (m messageForms) form(locale string, count any) string {
+	if count != nil && len(m) > 1 {
+		if message, ok := m[Plural(locale, count)]; ok {
+			return message
+		}
+	}
+	return m[PluralOther]
+}
+
+// Localize returns the message of code in the locale negotiated for c. It can
+// be used as I18n.Localize.
+func (l *Localizer) Localize(c echo.Context, code string) (string, error) {
+	return l.LocalizeParams(c, code, nil)
+}
+
+// LocalizeParams returns the message of code in the locale negotiated for c.
+// It can be used as I18n.LocalizeParams.
+// The plural form is selected by the count parameter.
+// The locale the message was found in, or the negotiated locale when it is
+// missing, is reported with SetLocale.
+func (l *Localizer) LocalizeParams(c echo.Context, code string, params map[string]any) (string, error) {
+	locale := l.Locale(c)
+	forms, loc, ok := l.lookup(locale, code)
+	if !ok {
+		SetLocale(c, locale)
+		return "", ErrMessageNotFound
+	}
+	SetLocale(c, loc)
+	return forms.form(loc, params[l.config.CountParam]), nil
+}
+
+// Message returns the message of code in locale or its fallbacks.
+func (l *Localizer) Message(locale, code string) (string, bool) {
+	return l.MessageCount(locale, code, nil)
+}
+
+// MessageCount returns the message of code in locale or its fallbacks, in the
+// plural form of count.
+func (l *Localizer) MessageCount(locale, code string, count any) (string, bool) {
+	forms, loc, ok := l.lookup(locale, code)
+	if !ok {
+		return "", false
+	}
+	return forms.form(loc, count), true
+}
+
+// I18n returns an I18n configuration localizing with l.
+func (l *Localizer) I18n() *I18n {
+	return &I18n{
+		Enabled:        true,
+		LocalizeParams: l.LocalizeParams,
+	}
+}
+
+// Messages returns the messages loaded for locale, without fallbacks, as
+// plural forms by code. Plain messages only have the "other" form.
+func (l *Localizer) Messages(locale string) map[string]map[string]string {
+	bundle := (*l.bundles.Load())[NormalizeLocale(locale)]
+	messages := make(map[string]map[string]string, len(bundle))
+	for code, forms := range bundle {
+		m := make(map[string]string, len(forms))
+		for category, message := range forms {
+			m[category] = message
+		}
+		messages[code] = m
+	}
+	return messages
+}
+
+// Locales returns the loaded locales.
+func (l *Localizer) Locales() []string {
+	b := *l.bundles.Load()
+	locales := make([]string, 0, len(b))
+	for locale := range b {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Locale negotiates the locale of c from the query parameter, cookie, context
+// value and Accept-Language header, in this order. It returns the first
+// requested locale with a loaded fallback, or the default locale.
+func (l *Localizer) Locale(c echo.Context) string {
+	b := *l.bundles.Load()
+	for _, requested := range l.requested(c) {
+		for _, locale := range fallbacks(requested) {
+			if _, ok := b[locale]; ok {
+				return locale
+			}
+		}
+	}
+	return l.config.DefaultLocale
+}
+
+func (l *Localizer) requested(c echo.Context) []string {
+	var requested []string
+	if l.config.QueryParam != "" {
+		if v := c.QueryParam(l.config.QueryParam); v != "" {
+			requested = append(requested, v)
+		}
+	}
+	if l.config.Cookie != "" {
+		if cookie, err := c.Cookie(l.config.Cookie); err == nil && cookie.Value != "" {
+			requested = append(requested, cookie.Value)
+		}
+	}
+	if l.config.ContextKey != "" {
+		if v, ok := c.Get(l.config.ContextKey).(string); ok && v != "" {
+			requested = append(requested, v)
+		}
+	}
+	return append(requested, ParseAcceptLanguage(c.Request().Header.Get(HeaderAcceptLanguage))...)
+}
+
+// lookup returns the message of code and the locale it was found in.
+func (l *Localizer) lookup(locale, code string) (messageForms, string, bool) {
+	b := *l.bundles.Load()
+	chain := append(fallbacks(locale), fallbacks(l.config.DefaultLocale)...)
+	for _, loc := range chain {
+		if forms, ok := b[loc][code]; ok {
+			return forms, loc, true
+		}
+	}
+	return nil, "", false
+}
+
+// translationFile is a translation file read from FS.
+type translationFile struct {
+	name string
+	data []byte
+}
+
+// readFiles returns the translation files of FS that have a Decoder, and a
+// digest of their names and contents.
+func (l *Localizer) readFiles() ([]translationFile, string, error) {
+	entries, err := fs.ReadDir(l.config.FS, l.config.Dir)
+	if err != nil {
+		return nil, "", err
+	}
+	h := sha256.New()
+	var files []translationFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if _, ok := l.config.Decoders[path.Ext(entry.Name())]; !ok {
+			continue
+		}
+		data, err := fs.ReadFile(l.config.FS, path.Join(l.config.Dir, entry.Name()))
+		if err != nil {
+			return nil, "", err
+		}
+		h.Write([]byte(entry.Name()))
+		h.Write([]byte{0})
+		h.Write(data)
+		h.Write([]byte{0})
+		files = append(files, translationFile{name: entry.Name(), data: data})
+	}
+	return files, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// load decodes the translation files of FS and returns the digest of the
+// bytes it decoded, see readFiles. The whole set is rejected when a file is
+// invalid.
+func (l *Localizer) load() (bundles, string, error) {
+	files, digest, err := l.readFiles()
+	if err != nil {
+		return nil, "", err
+	}
+	b := bundles{}
+	for _, file := range files {
+		ext := path.Ext(file.name)
+		raw := map[string]any{}
+		if err := l.config.Decoders[ext](file.data, &raw); err != nil {
+			return nil, digest, fmt.Errorf("echoerror: decode %s: %w", file.name, err)
+		}
+		locale := NormalizeLocale(strings.TrimSuffix(file.name, ext))
+		bundle := b[locale]
+		if bundle == nil {
+			bundle = map[string]messageForms{}
+			b[locale] = bundle
+		}
+		for code, value := range raw {
+			forms, err := newMessageForms(value)
+			if err != nil {
+				return nil, digest, fmt.Errorf("echoerror: decode %s: message %q %w", file.name, code, err)
+			}
+			bundle[code] = forms
+		}
+	}
+	return b, digest, nil
+}
+
+func newMessageForms(value any) (messageForms, error) {
+	switch v := value.(type) {
+	case string:
+		return messageForms{PluralOther: v}, nil
+	case map[any]any:
+		// Nested maps of decoders such as gopkg.in/yaml.v2.
+		m := make(map[string]any, len(v))
+		for k, message := range v {
+			category, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("has a non-string plural category %v", k)
+			}
+			m[category] = message
+		}
+		return newMessageForms(m)
+	case map[string]any:
+		forms := messageForms{}
+		for category, message := range v {
+			switch category {
+			case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+			default:
+				return nil, fmt.Errorf("has unknown plural category %q", category)
+			}
+			s, ok := message.(string)
+			if !ok {
+				return nil, fmt.Errorf("has a non-string %q form", category)
+			}
+			forms[category] = s
+		}
+		if _, ok := forms[PluralOther]; !ok {
+			return nil, errors.New("has no \"other\" form")
+		}
+		return forms, nil
+	}
+	return nil, errors.New("is not a string or plural forms")
+}
+
+// SetLocale reports the locale a message was localized in, so Response can
+// announce it in the Content-Language header, or the locale it is missing in.
+// Custom I18n.Localize functions call it after a lookup.
+func SetLocale(c echo.Context, locale string) {
+	c.Set(localeContextKey, locale)
+}
+
+// NormalizeLocale returns a locale tag in the form "th-TH": underscores
+// become hyphens, the language is lower case and a region upper case.
+func NormalizeLocale(locale string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	for i, p := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(p)
+		case len(p) == 2:
+			parts[i] = strings.ToUpper(p)
+		case len(p) == 4:
+			parts[i] = strings.ToUpper(p[:1]) + strings.ToLower(p[1:])
+		default:
+			parts[i] = strings.ToLower(p)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// fallbacks returns locale followed by its parents, e.g. zh-Hant-TW, zh-Hant, zh.
+func fallbacks(locale string) []string {
+	locale = NormalizeLocale(locale)
+	if locale == "" {
+		return nil
+	}
+	chain := []string{locale}
+	for i := strings.LastIndex(locale, "-"); i > 0; i = strings.LastIndex(locale, "-") {
+		locale = locale[:i]
+		chain = append(chain, locale)
+	}
+	return chain
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header ordered
+// by quality, dropping the wildcard and locales with q=0.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var locales []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := strings.TrimSpace(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		locales = append(locales, weighted{locale: locale, q: q})
+	}
+	sort.SliceStable(locales, func(i, j int) bool {
+		return locales[i].q > locales[j].q
+	})
+
+	result := make([]string, len(locales))
+	for i, w := range locales {
+		result[i] = w.locale
+	}
+	return result
+}
+
+// NewLocalizer loads the translation files of config.
+func NewLocalizer(config LocalizerConfig) (*Localizer, error) {
+	if config.Dir == "" {
+		config.Dir = "."
+	}
+	if config.DefaultLocale == "" {
+		config.DefaultLocale = "en"
+	}
+	if config.CountParam == "" {
+		config.CountParam = "count"
+	}
+	decoders := map[string]Decoder{".json": json.Unmarshal}
+	for ext, d := range config.Decoders {
+		decoders[ext] = d
+	}
+	config.Decoders = decoders
+
+	l := &Localizer{config: config}
+	b, digest, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	l.bundles.Store(&b)
+	l.digest = digest
+	return l, nil
+}
@@ -0,0 +1,211 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var translations = fstest.MapFS{
+	"i18n/en.json":    {Data: []byte(`{"CUS001": "Custom error", "CUS002": "Another error"}`)},
+	"i18n/th.json":    {Data: []byte(`{"CUS001": "ข้อผิดพลาดที่กำหนดเอง"}`)},
+	"i18n/th-TH.json": {Data: []byte(`{}`)},
+	"i18n/ja.lines":   {Data: []byte("CUS001: カスタムエラー\n")},
+	"i18n/README.md":  {Data: []byte(`# translations`)},
+}
+
+// decodeLines decodes "code: message" lines.
+func decodeLines(data []byte, v any) error {
+	m := v.(*map[string]any)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		code, message, _ := strings.Cut(line, ":")
+		(*m)[strings.TrimSpace(code)] = strings.TrimSpace(message)
+	}
+	return nil
+}
+
+func newLocalizer(t *testing.T) *echoerror.Localizer {
+	localizer, err := echoerror.NewLocalizer(echoerror.LocalizerConfig{
+		FS:         translations,
+		Dir:        "i18n",
+		Decoders:   map[string]echoerror.Decoder{".lines": decodeLines},
+		QueryParam: "lang",
+		Cookie:     "lang",
+		ContextKey: "user.locale",
+	})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	return localizer
+}
+
+func TestLocalizerNegotiation(t *testing.T) {
+	localizer := newLocalizer(t)
+	app := echo.New()
+
+	cases := []struct {
+		query    string
+		cookie   string
+		context  string
+		accept   string
+		expected string
+	}{
+		{accept: "th-TH,th;q=0.9,en;q=0.8", expected: "th-TH"},
+		{accept: "fr-CA,ja;q=0.5,en;q=0.7", expected: "en"},
+		{accept: "fr-CA, ja;q=0.9", expected: "ja"},
+		{accept: "de", expected: "en"},
+		{query: "ja", accept: "th", expected: "ja"},
+		{cookie: "th_th", accept: "en", expected: "th-TH"},
+		{context: "ja", accept: "th", expected: "ja"},
+		{query: "xx", cookie: "th", expected: "th"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/?lang="+tc.query, nil)
+		req.Header.Set(echoerror.HeaderAcceptLanguage, tc.accept)
+		if tc.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "lang", Value: tc.cookie})
+		}
+		c := app.NewContext(req, httptest.NewRecorder())
+		if tc.context != "" {
+			c.Set("user.locale", tc.context)
+		}
+
+		if actual := localizer.Locale(c); actual != tc.expected {
+			t.Error("Error", tc, actual)
+		}
+	}
+}
+
+func TestLocalizerFallback(t *testing.T) {
+	localizer := newLocalizer(t)
+
+	cases := []struct {
+		locale   string
+		code     string
+		expected string
+	}{
+		{"th-TH", "CUS001", "ข้อผิดพลาดที่กำหนดเอง"},
+		{"th-TH", "CUS002", "Another error"},
+		{"ja", "CUS001", "カスタムエラー"},
+	}
+	for _, tc := range cases {
+		if actual, ok := localizer.Message(tc.locale, tc.code); !ok || actual != tc.expected {
+			t.Error("Error", tc, actual)
+		}
+	}
+	if _, ok := localizer.Message("th", "CUS999"); ok {
+		t.Error("Error")
+	}
+	if locales := localizer.Locales(); strings.Join(locales, ",") != "en,ja,th,th-TH" {
+		t.Error("Error", locales)
+	}
+}
+
+func TestLocalizerResponse(t *testing.T) {
+	app := echo.New()
+	customResp := NewCustomResponse()
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+		I18n:   newLocalizer(t).I18n(),
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echoerror.HeaderAcceptLanguage, "th-TH")
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = res.With(c).Response(NewCustomError())
+
+	actual := goerror.Body{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if actual.Message != "ข้อผิดพลาดที่กำหนดเอง" {
+		t.Error("Error", resp.Body.String())
+	}
+	if resp.Header().Get(echoerror.HeaderContentLanguage) != "th" || resp.Header().Get(echo.HeaderVary) != echoerror.HeaderAcceptLanguage {
+		t.Error("Error", resp.Header())
+	}
+}
+
+func TestLocalizeContentLanguage(t *testing.T) {
+	app := echo.New()
+	customResp := NewCustomResponse()
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+		I18n: &echoerror.I18n{
+			Enabled: true,
+			Localize: func(c echo.Context, code string) (string, error) {
+				echoerror.SetLocale(c, "th-TH")
+				return "ข้อผิดพลาด", nil
+			},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+	c.Response().Header().Set(echo.HeaderVary, "Accept-Encoding, accept-language")
+
+	_ = res.With(c).Response(NewCustomError())
+
+	if resp.Header().Get(echoerror.HeaderContentLanguage) != "th-TH" || len(resp.Header().Values(echo.HeaderVary)) != 1 {
+		t.Error("Error", resp.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	resp = httptest.NewRecorder()
+	c = app.NewContext(req, resp)
+
+	_ = res.With(c).Response(goerror.NewNotFound())
+
+	if resp.Header().Get(echoerror.HeaderContentLanguage) != "" || resp.Header().Get(echo.HeaderVary) != "" {
+		t.Error("Error", resp.Header())
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	actual := echoerror.ParseAcceptLanguage("en;q=0.5, th-TH, *;q=0.1, fr;q=0, ja;q=0.8")
+	if strings.Join(actual, ",") != "th-TH,ja,en" {
+		t.Error("Error", actual)
+	}
+}
+
+// decodeYAMLv2 decodes "code.category: message" lines into the nested
+// map[any]any values gopkg.in/yaml.v2 produces.
+func decodeYAMLv2(data []byte, v any) error {
+	m := v.(*map[string]any)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		key, message, _ := strings.Cut(line, ":")
+		code, category, _ := strings.Cut(strings.TrimSpace(key), ".")
+		forms, ok := (*m)[code].(map[any]any)
+		if !ok {
+			forms = map[any]any{}
+			(*m)[code] = forms
+		}
+		forms[category] = strings.TrimSpace(message)
+	}
+	return nil
+}
+
+func TestLocalizerNestedMapDecoder(t *testing.T) {
+	localizer, err := echoerror.NewLocalizer(echoerror.LocalizerConfig{
+		FS: fstest.MapFS{
+			"en.yaml": {Data: []byte("ITEMS_FAILED.one: {{.count}} item failed\nITEMS_FAILED.other: {{.count}} items failed\n")},
+		},
+		Decoders: map[string]echoerror.Decoder{".yaml": decodeYAMLv2},
+	})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	if actual, _ := localizer.MessageCount("en", "ITEMS_FAILED", 1); actual != "{{.count}} item failed" {
+		t.Error("Error", actual)
+	}
+	if actual, _ := localizer.MessageCount("en", "ITEMS_FAILED", 2); actual != "{{.count}} items failed" {
+		t.Error("Error", actual)
+	}
+}
@@ -0,0 +1,88 @@
+package echoerror
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/prongbang/goerror"
+	"io/fs"
+	"net"
+)
+
+// Mapper translates an error into a goerror type before rendering. It returns
+// nil for errors it does not handle.
+type Mapper func(err error) error
+
+// SQLMapper maps sql.ErrNoRows to NotFound.
+func SQLMapper(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return goerror.NewNotFound()
+	}
+	return nil
+}
+
+// FSMapper maps fs.ErrNotExist to NotFound and fs.ErrPermission to Forbidden.
+func FSMapper(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return goerror.NewNotFound()
+	case errors.Is(err, fs.ErrPermission):
+		return goerror.NewForbidden()
+	}
+	return nil
+}
+
+// JSONMapper maps *json.SyntaxError and *json.UnmarshalTypeError to
+// BadRequest, with the offset and field of the problem as data.
+func JSONMapper(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		e := goerror.NewBadRequest().(*goerror.BadRequest)
+		e.Data = map[string]any{
+			"offset": syntaxErr.Offset,
+		}
+		return e
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		e := goerror.NewBadRequest().(*goerror.BadRequest)
+		data := map[string]any{
+			"offset": typeErr.Offset,
+			"value":  typeErr.Value,
+		}
+		if typeErr.Field != "" {
+			data["field"] = typeErr.Field
+		}
+		if typeErr.Type != nil {
+			data["expected"] = typeErr.Type.String()
+		}
+		e.Data = data
+		return e
+	}
+	return nil
+}
+
+// NetMapper maps failures to reach an upstream service: *net.DNSError to
+// BadGateway and a failed dial, e.g. a refused connection, to
+// ServiceUnavailable. Timeouts are left to the GatewayTimeout of deadlines.
+func NetMapper(err error) error {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return nil
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return goerror.NewBadGateway()
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return goerror.NewServiceUnavailable()
+	}
+	return nil
+}
+
+// StdlibMappers returns the mappers for errors of the standard library:
+// SQLMapper, FSMapper, JSONMapper and NetMapper.
+func StdlibMappers() []Mapper {
+	return []Mapper{SQLMapper, FSMapper, JSONMapper, NetMapper}
+}
@@ -0,0 +1,81 @@
+package echoerror_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestStdlibMappers(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Mappers: echoerror.StdlibMappers(),
+	})
+
+	_, notExist := os.Open("/does/not/exist")
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{fmt.Errorf("find user: %w", sql.ErrNoRows), http.StatusNotFound},
+		{notExist, http.StatusNotFound},
+		{&fs.PathError{Op: "open", Path: "/etc/shadow", Err: fs.ErrPermission}, http.StatusForbidden},
+		{json.Unmarshal([]byte(`{"name":`), &struct{}{}), http.StatusBadRequest},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "db.internal", IsNotFound: true}}, http.StatusBadGateway},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, http.StatusServiceUnavailable},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "i/o timeout", Name: "db.internal", IsTimeout: true}}, http.StatusGatewayTimeout},
+		{&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := app.NewContext(req, resp)
+
+		_ = res.With(c).Response(tc.err)
+
+		if resp.Code != tc.code {
+			t.Error("Error", tc.err, resp.Code)
+		}
+	}
+}
+
+func TestJSONMapperBind(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Mappers: []echoerror.Mapper{echoerror.JSONMapper},
+	})
+
+	type User struct {
+		Age int `json:"age"`
+	}
+	app.POST("/users", func(c echo.Context) error {
+		user := User{}
+		if err := c.Bind(&user); err != nil {
+			return res.With(c).Response(err)
+		}
+		return c.NoContent(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"age":"ten"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	actual := goerror.Body{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	data, _ := actual.Data.(map[string]any)
+	if resp.Code != http.StatusBadRequest || data["field"] != "age" || data["expected"] != "int" {
+		t.Error("Error", resp.Code, resp.Body.String())
+	}
+}
@@ -0,0 +1,55 @@
+package echoerror
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	configContextKey   = "echoerror.config"
+	responseContextKey = "echoerror.response"
+)
+
+var defaultResponse = New()
+
+// Use returns a middleware that stores r in the echo.Context, so handlers in
+// any package can render errors with Respond.
+func Use(r Response) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(responseContextKey, r)
+			return next(c)
+		}
+	}
+}
+
+// Respond renders err with the Response stored by Use, falling back to a
+// Response with the default configuration.
+func Respond(c echo.Context, err error) error {
+	r, ok := c.Get(responseContextKey).(Response)
+	if !ok {
+		r = defaultResponse
+	}
+	return r.With(c).Response(err)
+}
+
+// Override returns a middleware that merges cfg over the global configuration
+// of Response for the routes or group it is applied to. Nil fields keep the
+// global value, and overrides of nested groups are merged in order.
+func Override(cfg *Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			merged := cfg
+			if parent, ok := c.Get(configContextKey).(*Config); ok {
+				merged = parent.merge(cfg)
+			}
+			c.Set(configContextKey, merged)
+			return next(c)
+		}
+	}
+}
+
+// UseRenderer returns a middleware that makes Response render errors of the
+// routes or group it is applied to with r, overriding Config.Renderer.
+func UseRenderer(r Renderer) echo.MiddlewareFunc {
+	return Override(&Config{Renderer: r})
+}
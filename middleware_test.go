@@ -0,0 +1,86 @@
+package echoerror_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOverrideGroup(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Renderer: echoerror.NewJSONAPIRenderer(),
+	})
+	handler := func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewNotFound())
+	}
+
+	app.GET("/public", handler)
+	admin := app.Group("/admin", echoerror.Override(&echoerror.Config{
+		Envelope: &echoerror.Envelope{Key: "error", Omit: []string{"data"}},
+	}))
+	admin.GET("/users", handler)
+	admin.GET("/graphql", handler, echoerror.UseRenderer(echoerror.NewGraphQLRenderer()))
+
+	req := httptest.NewRequest(http.MethodGet, "/public", nil)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if ct := resp.Header().Get(echo.HeaderContentType); ct != echoerror.MIMEApplicationJSONAPI {
+		t.Error("Error", ct)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusNotFound || resp.Body.String() != `{"error":{"code":"CLE004","message":"Not Found"}}`+"\n" {
+		t.Error("Error", resp.Code, resp.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/graphql", nil)
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Error("Error", resp.Code, resp.Body.String())
+	}
+}
+
+func TestRespond(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Renderer: echoerror.NewJSONAPIRenderer(),
+	})
+	handler := func(c echo.Context) error {
+		return echoerror.Respond(c, goerror.NewConflict())
+	}
+
+	app.GET("/configured", handler, echoerror.Use(res))
+	app.GET("/default", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/configured", nil)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusConflict {
+		t.Error("Error", resp.Code)
+	}
+	if ct := resp.Header().Get(echo.HeaderContentType); ct != echoerror.MIMEApplicationJSONAPI {
+		t.Error("Error", ct)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/default", nil)
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusConflict {
+		t.Error("Error", resp.Code)
+	}
+	if ct := resp.Header().Get(echo.HeaderContentType); ct != echo.MIMEApplicationJSONCharsetUTF8 {
+		t.Error("Error", ct)
+	}
+}
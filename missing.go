@@ -0,0 +1,113 @@
+package echoerror
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MissingTranslation counts the failed localizations of a code in a locale.
+type MissingTranslation struct {
+	Code     string    `json:"code"`
+	Locale   string    `json:"locale"`
+	Count    int64     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// TestingT is the part of testing.TB used by MissingTranslations.AssertNone.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// MissingTranslations records the codes I18n failed to localize. It is safe
+// for concurrent use.
+type MissingTranslations struct {
+	mu      sync.Mutex
+	missing map[[2]string]*MissingTranslation
+	limit   int
+	dropped int64
+}
+
+// Record counts a failed localization of code in locale. Once the limit is
+// reached, new code and locale pairs are dropped, so locales sent by clients
+// cannot grow the store without bound.
+func (m *MissingTranslations) Record(code, locale string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := [2]string{code, locale}
+	mt, ok := m.missing[key]
+	if !ok {
+		if len(m.missing) >= m.limit {
+			m.dropped++
+			return
+		}
+		mt = &MissingTranslation{Code: code, Locale: locale}
+		m.missing[key] = mt
+	}
+	mt.Count++
+	mt.LastSeen = time.Now()
+}
+
+// List returns the missing translations ordered by code and locale.
+func (m *MissingTranslations) List() []MissingTranslation {
+	m.mu.Lock()
+	result := make([]MissingTranslation, 0, len(m.missing))
+	for _, mt := range m.missing {
+		result = append(result, *mt)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Code != result[j].Code {
+			return result[i].Code < result[j].Code
+		}
+		return result[i].Locale < result[j].Locale
+	})
+	return result
+}
+
+// Dropped returns the number of failed localizations not recorded because
+// the limit was reached.
+func (m *MissingTranslations) Dropped() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dropped
+}
+
+// Reset forgets the recorded translations.
+func (m *MissingTranslations) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.missing = map[[2]string]*MissingTranslation{}
+	m.dropped = 0
+}
+
+// Handler returns an Echo handler that lists the missing translations as JSON.
+func (m *MissingTranslations) Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, m.List())
+	}
+}
+
+// AssertNone fails the test for every recorded missing translation.
+func (m *MissingTranslations) AssertNone(t TestingT) {
+	t.Helper()
+	for _, mt := range m.List() {
+		t.Errorf("echoerror: missing translation of %s in locale %q (%d times)", mt.Code, mt.Locale, mt.Count)
+	}
+}
+
+// NewMissingTranslations returns an empty MissingTranslations recording at
+// most limit code and locale pairs. A limit of 0 defaults to 1000.
+func NewMissingTranslations(limit int) *MissingTranslations {
+	if limit <= 0 {
+		limit = 1000
+	}
+	return &MissingTranslations{
+		missing: map[[2]string]*MissingTranslation{},
+		limit:   limit,
+	}
+}
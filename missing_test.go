@@ -0,0 +1,131 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMissingTranslations(t *testing.T) {
+	missing := echoerror.NewMissingTranslations(0)
+	i18n := newLocalizer(t).I18n()
+	i18n.Missing = missing
+
+	app := echo.New()
+	customResp := echoerror.Custom(&validationResponse{})
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+		I18n:   i18n,
+	})
+	app.GET("/", func(c echo.Context) error {
+		return res.With(c).Response(&ValidationError{Body: goerror.Body{Code: "VAL404"}})
+	})
+	app.GET("/admin/missing", missing.Handler())
+
+	for _, lang := range []string{"th-TH", "th", "ja", "th"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echoerror.HeaderAcceptLanguage, lang)
+		resp := httptest.NewRecorder()
+		app.ServeHTTP(resp, req)
+
+		actual := goerror.Body{}
+		_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+		if actual.Message != "VAL404" || resp.Header().Get(echoerror.HeaderContentLanguage) != "" {
+			t.Error("Error", resp.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/missing", nil)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	actual := []echoerror.MissingTranslation{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if len(actual) != 3 || actual[0].Locale != "ja" || actual[1].Locale != "th" || actual[1].Count != 2 || actual[2].Locale != "th-TH" {
+		t.Error("Error", resp.Body.String())
+	}
+
+	rt := &recordingT{}
+	missing.AssertNone(rt)
+	if len(rt.errors) != 3 {
+		t.Error("Error", rt.errors)
+	}
+
+	missing.Reset()
+	missing.AssertNone(t)
+}
+
+func TestLocalizeFallbackMessage(t *testing.T) {
+	missing := echoerror.NewMissingTranslations(0)
+	app := echo.New()
+	customResp := NewCustomResponse()
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+		I18n: &echoerror.I18n{
+			Enabled:  true,
+			Missing:  missing,
+			Fallback: "Something went wrong",
+			Localize: func(c echo.Context, code string) (string, error) {
+				return "", nil
+			},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echoerror.HeaderAcceptLanguage, "en-us;q=0.5, fr")
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = res.With(c).Response(NewCustomError())
+
+	actual := goerror.Body{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if actual.Message != "Something went wrong" {
+		t.Error("Error", resp.Body.String())
+	}
+	if list := missing.List(); len(list) != 1 || list[0].Code != "CUS001" || list[0].Locale != "fr" {
+		t.Error("Error", list)
+	}
+}
+
+func TestMissingTranslationsLimit(t *testing.T) {
+	missing := echoerror.NewMissingTranslations(10)
+	app := echo.New()
+	customResp := NewCustomResponse()
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+		I18n: &echoerror.I18n{
+			Enabled: true,
+			Missing: missing,
+			Localize: func(c echo.Context, code string) (string, error) {
+				return "", echoerror.ErrMessageNotFound
+			},
+		},
+	})
+
+	for i := 0; i < 1000; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echoerror.HeaderAcceptLanguage, fmt.Sprintf("zz-%d", i))
+		c := app.NewContext(req, httptest.NewRecorder())
+		_ = res.With(c).Response(NewCustomError())
+	}
+
+	if len(missing.List()) != 10 || missing.Dropped() != 990 {
+		t.Error("Error", len(missing.List()), missing.Dropped())
+	}
+}
@@ -0,0 +1,75 @@
+package echoerror
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/goerror"
+	"time"
+)
+
+// Event describes an error rendered by Response. Err is the error passed to
+// Response, Code and Message describe the error rendered for it, e.g. a
+// DeadlineExceeded for context.DeadlineExceeded. Status is the status of the
+// rendered goerror type, even when a Renderer writes another one, e.g. 200 for
+// GraphQL; for other errors it is the status written by Custom. Committed
+// events were returned after the response was committed.
+type Event struct {
+	Time        time.Time `json:"time"`
+	Method      string    `json:"method"`
+	Route       string    `json:"route"`
+	Path        string    `json:"path"`
+	Status      int       `json:"status"`
+	Code        string    `json:"code"`
+	Message     string    `json:"message"`
+	RequestID   string    `json:"request_id,omitempty"`
+	Fingerprint string    `json:"fingerprint"`
+	Committed   bool      `json:"committed,omitempty"`
+	Err         error     `json:"-"`
+}
+
+// Observer is notified of every error rendered by Response.
+type Observer interface {
+	Observe(c echo.Context, e Event)
+}
+
+// ObserverFunc adapts a function to an Observer.
+type ObserverFunc func(c echo.Context, e Event)
+
+// Observe implements Observer.
+func (f ObserverFunc) Observe(c echo.Context, e Event) {
+	f(c, e)
+}
+
+// newEvent describes err after it has been rendered to c as rendered.
+func newEvent(c echo.Context, err error, rendered error) Event {
+	req := c.Request()
+	res := c.Response()
+	e := Event{
+		Time:      time.Now(),
+		Method:    req.Method,
+		Route:     c.Path(),
+		Path:      req.URL.Path,
+		Status:    res.Status,
+		RequestID: RequestID(c),
+		Err:       err,
+	}
+	// Response(nil) has no error to describe.
+	if rendered != nil {
+		if body, e1 := goerror.GetBody(rendered); e1 == nil {
+			e.Code = body.Code
+			e.Message = body.Message
+		} else {
+			e.Message = err.Error()
+		}
+	}
+	e.Fingerprint = Fingerprint(e)
+	return e
+}
+
+// RequestID returns the request ID set by the RequestID middleware, or the one
+// sent by the client.
+func RequestID(c echo.Context) string {
+	if id := c.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	return c.Request().Header.Get(echo.HeaderXRequestID)
+}
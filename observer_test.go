@@ -0,0 +1,63 @@
+package echoerror_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEventStatus(t *testing.T) {
+	var statuses []int
+	observer := echoerror.ObserverFunc(func(c echo.Context, e echoerror.Event) {
+		statuses = append(statuses, e.Status)
+	})
+
+	app := echo.New()
+	customResp := echoerror.Custom(&validationResponse{})
+	res := echoerror.New(&echoerror.Config{
+		Custom:    &customResp,
+		Observers: []echoerror.Observer{observer},
+	})
+	app.POST("/graphql", func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewInternalServerError())
+	}, echoerror.UseRenderer(echoerror.NewGraphQLRenderer()))
+	app.GET("/events", func(c echo.Context) error {
+		c.Response().WriteHeader(http.StatusOK)
+		return res.With(c).Response(goerror.NewInternalServerError())
+	}, echoerror.UseRenderer(echoerror.NewSSERenderer()))
+	app.GET("/validation", func(c echo.Context) error {
+		return res.With(c).Response(&ValidationError{Body: goerror.Body{Code: "VAL001"}})
+	})
+
+	for _, target := range []string{"/graphql", "/events", "/validation"} {
+		method := http.MethodGet
+		if target == "/graphql" {
+			method = http.MethodPost
+		}
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, target, nil))
+	}
+
+	if len(statuses) != 3 || statuses[0] != http.StatusInternalServerError || statuses[1] != http.StatusInternalServerError ||
+		statuses[2] != http.StatusUnprocessableEntity {
+		t.Error("Error", statuses)
+	}
+}
+
+func TestEventNilError(t *testing.T) {
+	var events []echoerror.Event
+	observer := echoerror.ObserverFunc(func(c echo.Context, e echoerror.Event) {
+		events = append(events, e)
+	})
+	res := echoerror.New(&echoerror.Config{Observers: []echoerror.Observer{observer}})
+
+	resp := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), resp)
+	_ = res.With(c).Response(nil)
+
+	if resp.Code != http.StatusBadRequest || len(events) != 1 || events[0].Err != nil || events[0].Status != http.StatusBadRequest {
+		t.Error("Error", resp.Code, events)
+	}
+}
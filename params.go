@@ -0,0 +1,163 @@
+package echoerror
+
+import (
+	"container/list"
+	"github.com/prongbang/goerror"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Params is implemented by errors that carry named parameters for their
+// message template, e.g. {"limit": 10, "resource": "projects"} for
+// "Quota of {{.limit}} exceeded for {{.resource}}".
+type Params interface {
+	Params() map[string]any
+}
+
+type paramsError struct {
+	err    error
+	params map[string]any
+}
+
+// Error implements error.
+func (p *paramsError) Error() string {
+	return p.err.Error()
+}
+
+// Unwrap returns the error carrying the parameters.
+func (p *paramsError) Unwrap() error {
+	return p.err
+}
+
+// Params implements Params.
+func (p *paramsError) Params() map[string]any {
+	return p.params
+}
+
+// WithParams attaches named parameters to err. Response renders err itself,
+// with its message interpolated with params; see Interpolate for the messages
+// that may be used as templates.
+func WithParams(err error, params map[string]any) error {
+	return &paramsError{err: err, params: params}
+}
+
+// splitParams returns the error to render and its parameters.
+func splitParams(err error) (error, map[string]any) {
+	switch p := err.(type) {
+	case *paramsError:
+		return p.err, p.params
+	case Params:
+		return err, p.Params()
+	}
+	return err, nil
+}
+
+// templateCacheSize is the number of parsed message templates kept.
+const templateCacheSize = 256
+
+// templates caches parsed message templates; the least recently used one is
+// evicted, so messages built at run time do not grow it without bound.
+var templates = &templateCache{
+	entries: map[string]*list.Element{},
+	lru:     list.New(),
+}
+
+type templateCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type templateEntry struct {
+	message string
+	tmpl    *template.Template
+}
+
+func (c *templateCache) get(message string) (*template.Template, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[message]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*templateEntry).tmpl, true
+}
+
+func (c *templateCache) add(message string, tmpl *template.Template) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[message]; ok {
+		return
+	}
+	for c.lru.Len() >= templateCacheSize {
+		el := c.lru.Back()
+		delete(c.entries, el.Value.(*templateEntry).message)
+		c.lru.Remove(el)
+	}
+	c.entries[message] = c.lru.PushFront(&templateEntry{message: message, tmpl: tmpl})
+}
+
+// Interpolate executes message as a Go template with params, e.g.
+// "Quota of {{.limit}} exceeded". Parameters are inserted as plain text;
+// escaping is left to the Renderer that encodes the message for its output
+// format. Messages without actions are returned unchanged.
+//
+// The message is trusted: a template can print every parameter, e.g.
+// {{printf "%v" .}}, so it must come from code or a translation catalog and
+// never contain client input.
+func Interpolate(message string, params map[string]any) (string, error) {
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, ok := templates.get(message)
+	if !ok {
+		t, err := template.New("message").Option("missingkey=error").Parse(message)
+		if err != nil {
+			return message, err
+		}
+		templates.add(message, t)
+		tmpl = t
+	}
+
+	sb := strings.Builder{}
+	if err := tmpl.Execute(&sb, params); err != nil {
+		return message, err
+	}
+	return sb.String(), nil
+}
+
+// interpolate returns err with its message template executed with params.
+// err is returned unchanged when the template fails.
+func interpolate(err error, params map[string]any) error {
+	if len(params) == 0 {
+		return err
+	}
+	body, e1 := goerror.GetBody(err)
+	if e1 != nil {
+		return err
+	}
+	message, e2 := Interpolate(body.Message, params)
+	if e2 != nil || message == body.Message {
+		return err
+	}
+	return withMessage(err, message)
+}
+
+// withMessage returns a shallow copy of err with the given message. The
+// error itself is often shared, e.g. a package-level value, so it is never
+// changed.
+func withMessage(err error, message string) error {
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return err
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	copied := c.Interface().(error)
+	goerror.SetMessage(copied, message)
+	return copied
+}
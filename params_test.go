@@ -0,0 +1,156 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type QuotaError struct {
+	goerror.Body
+	Limit    int
+	Resource string
+}
+
+// Error implements error.
+func (q *QuotaError) Error() string {
+	return q.Message
+}
+
+// Params implements echoerror.Params.
+func (q *QuotaError) Params() map[string]any {
+	return map[string]any{"limit": q.Limit, "resource": q.Resource}
+}
+
+type quotaResponse struct {
+}
+
+// Response implements response.Custom.
+func (q *quotaResponse) Response(ctx echo.Context, err error) error {
+	switch e := err.(type) {
+	case *QuotaError:
+		return ctx.JSON(http.StatusTooManyRequests, e)
+	}
+	return nil
+}
+
+func TestWithParams(t *testing.T) {
+	app := echo.New()
+
+	handler := func(c echo.Context) error {
+		err := goerror.NewTooManyRequests()
+		goerror.SetMessage(err, "Quota of {{.limit}} exceeded for {{.resource}}")
+		return response.With(c).Response(echoerror.WithParams(err, map[string]any{
+			"limit":    10,
+			"resource": "<projects>",
+		}))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = handler(c)
+
+	if resp.Code != http.StatusTooManyRequests || !strings.Contains(resp.Body.String(), `\u003cprojects\u003e`) {
+		t.Error("Error", resp.Code, resp.Body.String())
+	}
+	actual := goerror.Body{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if actual.Message != "Quota of 10 exceeded for <projects>" {
+		t.Error("Error", actual.Message)
+	}
+}
+
+func TestLocalizeParams(t *testing.T) {
+	app := echo.New()
+	customResp := echoerror.Custom(&quotaResponse{})
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+		I18n: &echoerror.I18n{
+			Enabled: true,
+			LocalizeParams: func(c echo.Context, code string, params map[string]any) (string, error) {
+				return "เกินโควตา {{.limit}} สำหรับ {{.resource}}", nil
+			},
+		},
+	})
+
+	handler := func(c echo.Context) error {
+		return res.With(c).Response(&QuotaError{
+			Body:     goerror.Body{Code: "QUO001"},
+			Limit:    5,
+			Resource: "projects",
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = handler(c)
+
+	actual := goerror.Body{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if resp.Code != http.StatusTooManyRequests || actual.Message != "เกินโควตา 5 สำหรับ projects" {
+		t.Error("Error", resp.Code, resp.Body.String())
+	}
+}
+
+func TestInterpolateMissingParam(t *testing.T) {
+	message := "Quota of {{.limit}} exceeded"
+	actual, err := echoerror.Interpolate(message, map[string]any{})
+	if err == nil || actual != message {
+		t.Error("Error", actual, err)
+	}
+}
+
+func TestInterpolateManyMessages(t *testing.T) {
+	params := map[string]any{"limit": 10}
+	for i := 0; i < 1000; i++ {
+		message := fmt.Sprintf("Quota %d of {{.limit}} exceeded", i)
+		if actual, err := echoerror.Interpolate(message, params); err != nil || actual != fmt.Sprintf("Quota %d of 10 exceeded", i) {
+			t.Fatal("Error", actual, err)
+		}
+	}
+	// Evicted templates are parsed again.
+	if actual, err := echoerror.Interpolate("Quota 0 of {{.limit}} exceeded", params); err != nil || actual != "Quota 0 of 10 exceeded" {
+		t.Error("Error", actual, err)
+	}
+}
+
+func TestWithParamsSharedError(t *testing.T) {
+	errQuota := goerror.NewTooManyRequests()
+	goerror.SetMessage(errQuota, "Quota of {{.limit}} exceeded")
+
+	app := echo.New()
+	app.GET("/", func(c echo.Context) error {
+		return response.With(c).Response(echoerror.WithParams(errQuota, map[string]any{"limit": c.QueryParam("limit")}))
+	})
+
+	var wg sync.WaitGroup
+	for _, limit := range []string{"10", "20", "30", "40"} {
+		wg.Add(1)
+		go func(limit string) {
+			defer wg.Done()
+			resp := httptest.NewRecorder()
+			app.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/?limit="+limit, nil))
+			actual := goerror.Body{}
+			_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+			if actual.Message != "Quota of "+limit+" exceeded" {
+				t.Error("Error", limit, resp.Body.String())
+			}
+		}(limit)
+	}
+	wg.Wait()
+
+	if body, _ := goerror.GetBody(errQuota); body.Message != "Quota of {{.limit}} exceeded" {
+		t.Error("Error", body.Message)
+	}
+}
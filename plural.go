@@ -0,0 +1,210 @@
+package echoerror
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CLDR plural categories.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// pluralOperands are the CLDR operands of a number: n absolute value, i
+// integer digits and v number of visible fraction digits.
+type pluralOperands struct {
+	n float64
+	i int64
+	v int
+}
+
+// pluralRule returns the plural category of a number.
+type pluralRule func(o pluralOperands) string
+
+// pluralRules are the cardinal rules of CLDR for common languages.
+var pluralRules = map[string]pluralRule{}
+
+func init() {
+	for _, lang := range []string{"th", "ja", "zh", "ko", "vi", "id", "ms", "lo", "my", "km"} {
+		pluralRules[lang] = pluralNone
+	}
+	for _, lang := range []string{"en", "de", "nl", "sv", "da", "no", "nb", "fi", "et", "it"} {
+		pluralRules[lang] = pluralOneIntegerOnly
+	}
+	pluralRules["es"] = pluralOneExact
+	pluralRules["fr"] = pluralZeroOne
+	pluralRules["pt"] = pluralZeroOne
+	pluralRules["ru"] = pluralEastSlavic
+	pluralRules["uk"] = pluralEastSlavic
+	pluralRules["be"] = pluralEastSlavic
+	pluralRules["pl"] = pluralPolish
+	pluralRules["cs"] = pluralCzech
+	pluralRules["sk"] = pluralCzech
+	pluralRules["ar"] = pluralArabic
+}
+
+func pluralNone(o pluralOperands) string {
+	return PluralOther
+}
+
+// one: i = 1 and v = 0
+func pluralOneIntegerOnly(o pluralOperands) string {
+	if o.i == 1 && o.v == 0 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// one: n = 1
+func pluralOneExact(o pluralOperands) string {
+	if o.n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// one: i = 0,1
+func pluralZeroOne(o pluralOperands) string {
+	if o.i == 0 || o.i == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// one: v = 0 and i % 10 = 1 and i % 100 != 11
+// few: v = 0 and i % 10 = 2..4 and i % 100 != 12..14
+// many: v = 0 and (i % 10 = 0 or i % 10 = 5..9 or i % 100 = 11..14)
+func pluralEastSlavic(o pluralOperands) string {
+	if o.v != 0 {
+		return PluralOther
+	}
+	i10, i100 := o.i%10, o.i%100
+	switch {
+	case i10 == 1 && i100 != 11:
+		return PluralOne
+	case i10 >= 2 && i10 <= 4 && (i100 < 12 || i100 > 14):
+		return PluralFew
+	}
+	return PluralMany
+}
+
+// one: i = 1 and v = 0
+// few: v = 0 and i % 10 = 2..4 and i % 100 != 12..14
+// many: v = 0 and (i != 1 and i % 10 = 0..1 or i % 10 = 5..9 or i % 100 = 12..14)
+func pluralPolish(o pluralOperands) string {
+	if o.v != 0 {
+		return PluralOther
+	}
+	i10, i100 := o.i%10, o.i%100
+	switch {
+	case o.i == 1:
+		return PluralOne
+	case i10 >= 2 && i10 <= 4 && (i100 < 12 || i100 > 14):
+		return PluralFew
+	}
+	return PluralMany
+}
+
+// one: i = 1 and v = 0
+// few: i = 2..4 and v = 0
+// many: v != 0
+func pluralCzech(o pluralOperands) string {
+	switch {
+	case o.v != 0:
+		return PluralMany
+	case o.i == 1:
+		return PluralOne
+	case o.i >= 2 && o.i <= 4:
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// zero: n = 0
+// one: n = 1
+// two: n = 2
+// few: n % 100 = 3..10
+// many: n % 100 = 11..99
+func pluralArabic(o pluralOperands) string {
+	n100 := math.Mod(o.n, 100)
+	switch {
+	case o.n == 0:
+		return PluralZero
+	case o.n == 1:
+		return PluralOne
+	case o.n == 2:
+		return PluralTwo
+	case o.v == 0 && n100 >= 3 && n100 <= 10:
+		return PluralFew
+	case o.v == 0 && n100 >= 11 && n100 <= 99:
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// Plural returns the CLDR plural category of count in locale. Counts are
+// integers, floats or their decimal string form such as "1.50", whose
+// visible fraction digits are kept. Languages without built-in rules follow
+// English.
+func Plural(locale string, count any) string {
+	o, ok := newPluralOperands(count)
+	if !ok {
+		return PluralOther
+	}
+	lang, _, _ := strings.Cut(NormalizeLocale(locale), "-")
+	rule, ok := pluralRules[lang]
+	if !ok {
+		rule = pluralOneIntegerOnly
+	}
+	return rule(o)
+}
+
+func newPluralOperands(count any) (pluralOperands, bool) {
+	var s string
+	switch v := count.(type) {
+	case int:
+		s = strconv.FormatInt(int64(v), 10)
+	case int8:
+		s = strconv.FormatInt(int64(v), 10)
+	case int16:
+		s = strconv.FormatInt(int64(v), 10)
+	case int32:
+		s = strconv.FormatInt(int64(v), 10)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case uint:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	case float32:
+		s = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		s = v
+	default:
+		return pluralOperands{}, false
+	}
+
+	s = strings.TrimPrefix(s, "-")
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return pluralOperands{}, false
+	}
+	integer, fraction, _ := strings.Cut(s, ".")
+	o := pluralOperands{n: n, v: len(fraction)}
+	o.i, _ = strconv.ParseInt(integer, 10, 64)
+	return o, true
+}
@@ -0,0 +1,93 @@
+package echoerror_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPlural(t *testing.T) {
+	cases := []struct {
+		locale   string
+		count    any
+		expected string
+	}{
+		{"en", 1, echoerror.PluralOne},
+		{"en-US", 3, echoerror.PluralOther},
+		{"en", "1.0", echoerror.PluralOther},
+		{"th-TH", 1, echoerror.PluralOther},
+		{"ru", 1, echoerror.PluralOne},
+		{"ru", 21, echoerror.PluralOne},
+		{"ru", 11, echoerror.PluralMany},
+		{"ru", 3, echoerror.PluralFew},
+		{"ru", 14, echoerror.PluralMany},
+		{"ru", 25, echoerror.PluralMany},
+		{"ru", 1.5, echoerror.PluralOther},
+		{"pl", 22, echoerror.PluralFew},
+		{"pl", 12, echoerror.PluralMany},
+		{"cs", 3, echoerror.PluralFew},
+		{"cs", 5, echoerror.PluralOther},
+		{"fr", 0, echoerror.PluralOne},
+		{"ar", 0, echoerror.PluralZero},
+		{"ar", 2, echoerror.PluralTwo},
+		{"ar", 105, echoerror.PluralFew},
+		{"ar", 111, echoerror.PluralMany},
+		{"xx", 1, echoerror.PluralOne},
+		{"en", nil, echoerror.PluralOther},
+	}
+	for _, tc := range cases {
+		if actual := echoerror.Plural(tc.locale, tc.count); actual != tc.expected {
+			t.Error("Error", tc, actual)
+		}
+	}
+}
+
+func TestLocalizerPlural(t *testing.T) {
+	localizer, err := echoerror.NewLocalizer(echoerror.LocalizerConfig{
+		FS: fstest.MapFS{
+			"en.json": {Data: []byte(`{"ITEMS_FAILED": {"one": "{{.count}} item failed", "other": "{{.count}} items failed"}}`)},
+			"ru.json": {Data: []byte(`{"ITEMS_FAILED": {"one": "{{.count}} элемент", "few": "{{.count}} элемента", "many": "{{.count}} элементов", "other": "{{.count}} элемента"}}`)},
+			"th.json": {Data: []byte(`{"ITEMS_FAILED": "{{.count}} รายการล้มเหลว"}`)},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	app := echo.New()
+
+	cases := []struct {
+		locale   string
+		count    int
+		expected string
+	}{
+		{"en", 1, "{{.count}} item failed"},
+		{"en", 3, "{{.count}} items failed"},
+		{"ru", 3, "{{.count}} элемента"},
+		{"ru", 5, "{{.count}} элементов"},
+		{"th", 3, "{{.count}} รายการล้มเหลว"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echoerror.HeaderAcceptLanguage, tc.locale)
+		c := app.NewContext(req, httptest.NewRecorder())
+
+		actual, err := localizer.LocalizeParams(c, "ITEMS_FAILED", map[string]any{"count": tc.count})
+		if err != nil || actual != tc.expected {
+			t.Error("Error", tc, actual)
+		}
+	}
+}
+
+func TestLocalizerInvalidPlural(t *testing.T) {
+	_, err := echoerror.NewLocalizer(echoerror.LocalizerConfig{
+		FS: fstest.MapFS{
+			"en.json": {Data: []byte(`{"ITEMS_FAILED": {"one": "{{.count}} item failed"}}`)},
+		},
+	})
+	if err == nil {
+		t.Error("Error")
+	}
+}
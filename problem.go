@@ -0,0 +1,60 @@
+package echoerror
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/goerror"
+	"net/http"
+)
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// ProblemDetails is a problem details object (RFC 9457). Code and Errors are
+// extension members carrying the goerror code and the field errors.
+type ProblemDetails struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code,omitempty"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+type problemRenderer struct {
+	TypeBase string
+}
+
+// Render implements Renderer.
+func (p *problemRenderer) Render(c echo.Context, code int, err error) error {
+	body, _ := goerror.GetBody(err)
+	detail := body.Message
+	if detail == "" {
+		detail = err.Error()
+	}
+
+	problem := ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Detail:   detail,
+		Instance: c.Request().URL.Path,
+		Code:     body.Code,
+	}
+	if p.TypeBase != "" && body.Code != "" {
+		problem.Type = p.TypeBase + body.Code
+	}
+	if fe, ok := err.(FieldErrors); ok {
+		problem.Errors = fe.FieldErrors()
+	}
+	return writeJSON(c, code, MIMEApplicationProblemJSON, problem)
+}
+
+// NewProblemRenderer returns a Renderer that writes errors as problem details
+// (RFC 9457). The problem type is typeBase followed by the error code, e.g.
+// "https://example.com/problems/CLE004", or "about:blank" when typeBase is
+// empty.
+func NewProblemRenderer(typeBase string) Renderer {
+	return &problemRenderer{
+		TypeBase: typeBase,
+	}
+}
@@ -0,0 +1,52 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemRenderer(t *testing.T) {
+	app := echo.New()
+	customResp := echoerror.Custom(&validationResponse{})
+	res := echoerror.New(&echoerror.Config{
+		Custom:   &customResp,
+		Renderer: echoerror.NewProblemRenderer("https://example.com/problems/"),
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	resp := httptest.NewRecorder()
+	c := app.NewContext(req, resp)
+
+	_ = res.With(c).Response(&ValidationError{
+		Body:   goerror.Body{Code: "VAL001", Message: "Validation failed"},
+		Fields: map[string]string{"email": "is invalid"},
+	})
+
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Error", resp.Code)
+	}
+	if ct := resp.Header().Get(echo.HeaderContentType); ct != echoerror.MIMEApplicationProblemJSON {
+		t.Error("Error", ct)
+	}
+	actual := echoerror.ProblemDetails{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if actual.Type != "https://example.com/problems/VAL001" || actual.Title != "Unprocessable Entity" || actual.Status != http.StatusUnprocessableEntity ||
+		actual.Detail != "Validation failed" || actual.Instance != "/users" || actual.Code != "VAL001" || actual.Errors["email"] != "is invalid" {
+		t.Error("Error", resp.Body.String())
+	}
+
+	resp = httptest.NewRecorder()
+	c = app.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), resp)
+	_ = echoerror.New(&echoerror.Config{Renderer: echoerror.NewProblemRenderer("")}).With(c).Response(goerror.NewNotFound())
+
+	actual = echoerror.ProblemDetails{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if actual.Type != "about:blank" || actual.Status != http.StatusNotFound || actual.Code != goerror.CodeNotFound {
+		t.Error("Error", resp.Body.String())
+	}
+}
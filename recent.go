@@ -0,0 +1,144 @@
+package echoerror
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecentErrors keeps the last rendered errors in a bounded ring buffer. It is
+// safe for concurrent use.
+type RecentErrors struct {
+	mu      sync.RWMutex
+	entries []Event
+	next    int
+	full    bool
+}
+
+// RecentFilter selects entries of RecentErrors. Zero fields match everything.
+type RecentFilter struct {
+	Status int
+	// Class matches the status class, e.g. 5 for 5xx.
+	Class int
+	Code  string
+	Route string
+	Since time.Time
+	Limit int
+}
+
+// Observe implements Observer.
+func (r *RecentErrors) Observe(c echo.Context, e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Entries returns the entries matching f, newest first.
+func (r *RecentErrors) Entries(f RecentFilter) []Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	size := r.next
+	if r.full {
+		size = len(r.entries)
+	}
+	result := []Event{}
+	for i := 1; i <= size; i++ {
+		e := r.entries[(r.next-i+len(r.entries))%len(r.entries)]
+		if !f.match(e) {
+			continue
+		}
+		result = append(result, e)
+		if f.Limit > 0 && len(result) == f.Limit {
+			break
+		}
+	}
+	return result
+}
+
+// Handler returns an Echo handler that lists the entries as JSON. Entries are
+// filtered by the query parameters status (e.g. 404 or 4xx), code, route,
+// since (RFC 3339) and limit.
+func (r *RecentErrors) Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		f, err := parseRecentFilter(c)
+		if err != nil {
+			return Respond(c, goerror.NewBadRequest())
+		}
+		return c.JSON(http.StatusOK, r.Entries(f))
+	}
+}
+
+func (f RecentFilter) match(e Event) bool {
+	if f.Status != 0 && e.Status != f.Status {
+		return false
+	}
+	if f.Class != 0 && e.Status/100 != f.Class {
+		return false
+	}
+	if f.Code != "" && e.Code != f.Code {
+		return false
+	}
+	if f.Route != "" && e.Route != f.Route {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+func parseRecentFilter(c echo.Context) (RecentFilter, error) {
+	f := RecentFilter{
+		Code:  c.QueryParam("code"),
+		Route: c.QueryParam("route"),
+	}
+	if status := strings.ToLower(c.QueryParam("status")); status != "" {
+		if len(status) == 3 && strings.HasSuffix(status, "xx") {
+			class, err := strconv.Atoi(status[:1])
+			if err != nil {
+				return f, err
+			}
+			f.Class = class
+		} else {
+			code, err := strconv.Atoi(status)
+			if err != nil {
+				return f, err
+			}
+			f.Status = code
+		}
+	}
+	if since := c.QueryParam("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return f, err
+		}
+		f.Since = t
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return f, err
+		}
+		f.Limit = n
+	}
+	return f, nil
+}
+
+// NewRecentErrors returns a RecentErrors holding at most size entries.
+func NewRecentErrors(size int) *RecentErrors {
+	if size < 1 {
+		size = 1
+	}
+	return &RecentErrors{
+		entries: make([]Event, size),
+	}
+}
@@ -0,0 +1,86 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestRecentErrors(t *testing.T) {
+	recent := echoerror.NewRecentErrors(2)
+	app := echo.New()
+	app.Use(middleware.RequestID())
+	res := echoerror.New(&echoerror.Config{
+		Observers: []echoerror.Observer{recent},
+	})
+
+	app.GET("/users/:id", func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewNotFound())
+	})
+	app.GET("/boom", func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewInternalServerError())
+	})
+	app.GET("/admin/errors", recent.Handler())
+
+	for _, path := range []string{"/boom", "/users/1", "/boom"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		app.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	entries := recent.Entries(echoerror.RecentFilter{})
+	if len(entries) != 2 || entries[0].Route != "/boom" || entries[1].Route != "/users/:id" {
+		t.Fatal("Error", entries)
+	}
+	if entries[1].Status != http.StatusNotFound || entries[1].Code != goerror.CodeNotFound || entries[1].RequestID == "" {
+		t.Error("Error", entries[1])
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/errors?status=4xx", nil)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	actual := []echoerror.Event{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &actual)
+	if resp.Code != http.StatusOK || len(actual) != 1 || actual[0].Code != goerror.CodeNotFound {
+		t.Error("Error", resp.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/errors?limit=x", nil)
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadRequest {
+		t.Error("Error", resp.Code)
+	}
+}
+
+func TestRecentErrorsConcurrent(t *testing.T) {
+	recent := echoerror.NewRecentErrors(16)
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Observers: []echoerror.Observer{recent},
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			c := app.NewContext(req, httptest.NewRecorder())
+			_ = res.With(c).Response(goerror.NewConflict())
+			_ = recent.Entries(echoerror.RecentFilter{Limit: 4})
+		}()
+	}
+	wg.Wait()
+
+	if entries := recent.Entries(echoerror.RecentFilter{}); len(entries) != 16 {
+		t.Error("Error", len(entries))
+	}
+}
@@ -0,0 +1,97 @@
+package echoerror
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Reload loads the translation files again and swaps them in at once.
+// Lookups in flight keep the messages they started with. When a file is
+// invalid, the previous messages are kept and the error is returned.
+func (l *Localizer) Reload() error {
+	l.reload.Lock()
+	defer l.reload.Unlock()
+
+	b, digest, err := l.load()
+	if err == nil {
+		l.bundles.Store(&b)
+	}
+	if digest != "" {
+		// Also for rejected files, so Watch waits for them to change again.
+		l.digest = digest
+	}
+	if l.config.OnReload != nil {
+		l.config.OnReload(err)
+	}
+	return err
+}
+
+// Watch polls the translation files every interval and reloads them when they
+// change, until ctx is done. Files that fail to load are retried once they
+// change again. Run it in its own goroutine.
+func (l *Localizer) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		digest, err := l.fingerprint()
+		if err != nil {
+			continue
+		}
+		l.reload.Lock()
+		changed := digest != l.digest
+		l.reload.Unlock()
+		if changed {
+			_ = l.Reload()
+		}
+	}
+}
+
+// ReloadOnSignal reloads the translation files on every signal received,
+// SIGHUP by default, until ctx is done. Run it in its own goroutine.
+func (l *Localizer) ReloadOnSignal(ctx context.Context, signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			_ = l.Reload()
+		}
+	}
+}
+
+// ReloadHandler returns an Echo handler that reloads the translation files. A
+// rejected file is reported as Unprocessable Entity with the reason.
+func (l *Localizer) ReloadHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := l.Reload(); err != nil {
+			e := goerror.NewUnprocessableEntity()
+			goerror.SetMessage(e, err.Error())
+			return Respond(c, e)
+		}
+		return c.JSON(http.StatusOK, map[string]any{"locales": l.Locales()})
+	}
+}
+
+// fingerprint returns a digest of the names and contents of the translation
+// files.
+func (l *Localizer) fingerprint() (string, error) {
+	_, digest, err := l.readFiles()
+	return digest, err
+}
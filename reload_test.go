@@ -0,0 +1,167 @@
+package echoerror_test
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeTranslation replaces a file at once, so Watch never reads it half
+// written.
+func writeTranslation(t *testing.T, dir, name, data string) {
+	t.Helper()
+	tmp := filepath.Join(dir, name+".tmp")
+	if err := os.WriteFile(tmp, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalizerReload(t *testing.T) {
+	dir := t.TempDir()
+	writeTranslation(t, dir, "en.json", `{"CUS001": "Custom error"}`)
+
+	var reloads []error
+	localizer, err := echoerror.NewLocalizer(echoerror.LocalizerConfig{
+		FS: os.DirFS(dir),
+		OnReload: func(err error) {
+			reloads = append(reloads, err)
+		},
+	})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if _, ok := localizer.Message("en", "CUS001"); !ok {
+				t.Error("Error")
+				return
+			}
+		}
+	}()
+
+	writeTranslation(t, dir, "en.json", `{"CUS001": "Updated error"}`)
+	writeTranslation(t, dir, "th.json", `{"CUS001": "ข้อผิดพลาด"}`)
+	if err := localizer.Reload(); err != nil {
+		t.Error("Error", err)
+	}
+	writeTranslation(t, dir, "th.json", `{"CUS001": ["invalid"]}`)
+	if err := localizer.Reload(); err == nil {
+		t.Error("Error")
+	}
+	close(stop)
+	wg.Wait()
+
+	if message, _ := localizer.Message("en", "CUS001"); message != "Updated error" {
+		t.Error("Error", message)
+	}
+	if message, _ := localizer.Message("th", "CUS001"); message != "ข้อผิดพลาด" {
+		t.Error("Error", message)
+	}
+	if len(reloads) != 2 || reloads[0] != nil || reloads[1] == nil {
+		t.Error("Error", reloads)
+	}
+}
+
+func TestLocalizerWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeTranslation(t, dir, "en.json", `{"CUS001": "Custom error"}`)
+
+	reloaded := make(chan error, 10)
+	localizer, err := echoerror.NewLocalizer(echoerror.LocalizerConfig{
+		FS: os.DirFS(dir),
+		OnReload: func(err error) {
+			reloaded <- err
+		},
+	})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go localizer.Watch(ctx, 10*time.Millisecond)
+
+	writeTranslation(t, dir, "en.json", `{"CUS001": "Updated error"}`)
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Error("Error", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Error: not reloaded")
+	}
+	if message, _ := localizer.Message("en", "CUS001"); message != "Updated error" {
+		t.Error("Error", message)
+	}
+
+	writeTranslation(t, dir, "en.json", `{`)
+	if err := <-reloaded; err == nil {
+		t.Error("Error")
+	}
+	if message, _ := localizer.Message("en", "CUS001"); message != "Updated error" {
+		t.Error("Error", message)
+	}
+
+	// The rejected file is not retried, so the next reload is the fixed one.
+	writeTranslation(t, dir, "en.json", `{"CUS001": "Fixed error"}`)
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Error("Error: rejected file reloaded again", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Error: not reloaded")
+	}
+	if message, _ := localizer.Message("en", "CUS001"); message != "Fixed error" {
+		t.Error("Error", message)
+	}
+}
+
+func TestLocalizerReloadHandler(t *testing.T) {
+	dir := t.TempDir()
+	writeTranslation(t, dir, "en.json", `{"CUS001": "Custom error"}`)
+	localizer, err := echoerror.NewLocalizer(echoerror.LocalizerConfig{FS: os.DirFS(dir)})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	app := echo.New()
+	app.POST("/admin/translations/reload", localizer.ReloadHandler())
+
+	writeTranslation(t, dir, "en.json", `{"CUS001": 1}`)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/admin/translations/reload", nil))
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Error", resp.Code, resp.Body.String())
+	}
+
+	writeTranslation(t, dir, "en.json", `{"CUS001": "Updated error"}`)
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/admin/translations/reload", nil))
+	if resp.Code != http.StatusOK {
+		t.Error("Error", resp.Code, resp.Body.String())
+	}
+	if message, _ := localizer.Message("en", "CUS001"); message != "Updated error" {
+		t.Error("Error", message)
+	}
+}
@@ -0,0 +1,52 @@
+package echoerror
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+)
+
+// Renderer writes an error to the client with the given HTTP status code.
+type Renderer interface {
+	Render(c echo.Context, code int, err error) error
+}
+
+// StatusRenderer is implemented by Renderers that write another status code
+// than the one of the error, e.g. 200 for GraphQL. Response leaves the body
+// out by the status the Renderer writes.
+type StatusRenderer interface {
+	Renderer
+	Status(c echo.Context, code int) int
+}
+
+// FieldErrors is implemented by validation errors that report problems per
+// field, keyed by field name.
+type FieldErrors interface {
+	FieldErrors() map[string]string
+}
+
+// renderContext routes the errors a Custom writes with JSON through the
+// Response, so custom error types share the configured output format.
+type renderContext struct {
+	echo.Context
+	render func(code int, err error) error
+}
+
+// JSON implements echo.Context.
+func (r *renderContext) JSON(code int, i interface{}) error {
+	if err, ok := i.(error); ok {
+		return r.render(code, err)
+	}
+	if Bodiless(r.Request().Method, code) {
+		return r.NoContent(code)
+	}
+	return r.Context.JSON(code, i)
+}
+
+// writeJSON encodes v as the response body with the given content type.
+func writeJSON(c echo.Context, code int, contentType string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Blob(code, contentType, b)
+}
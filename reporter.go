@@ -0,0 +1,318 @@
+package echoerror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrReporterClosed is returned by Flush after the reporter was closed.
+var ErrReporterClosed = errors.New("echoerror: reporter closed")
+
+// Reporter delivers a batch of error events to an external service.
+type Reporter interface {
+	Report(ctx context.Context, events []Event) error
+}
+
+// QueuePolicy decides what happens to an event when the queue is full.
+type QueuePolicy int
+
+const (
+	// PolicyDrop drops the event and counts it in Dropped.
+	PolicyDrop QueuePolicy = iota
+	// PolicyBlock blocks the request until the event is queued or the request
+	// is canceled.
+	PolicyBlock
+)
+
+type ReporterConfig struct {
+	// MinStatus is the lowest status reported. Defaults to 500.
+	MinStatus int
+	// Statuses are reported in addition to MinStatus and above, e.g. 401 or 429.
+	Statuses []int
+	// QueueSize bounds the events waiting for delivery. Defaults to 1024.
+	QueueSize int
+	// BatchSize is the most events delivered at once. Defaults to 50.
+	BatchSize int
+	// FlushInterval delivers a partial batch after this delay. Defaults to 5s.
+	FlushInterval time.Duration
+	// Timeout bounds a single delivery. Defaults to 10s.
+	Timeout time.Duration
+	Policy  QueuePolicy
+	// OnError is called when a delivery fails.
+	OnError func(err error)
+}
+
+// AsyncReporter is an Observer that queues events and delivers them in batches
+// to a Reporter from a background goroutine.
+type AsyncReporter struct {
+	reporter Reporter
+	config   ReporterConfig
+	queue    chan Event
+	flush    chan chan error
+	done     chan struct{}
+	stopped  chan struct{}
+	close    sync.Once
+	dropped  atomic.Uint64
+}
+
+// Observe implements Observer.
+func (a *AsyncReporter) Observe(c echo.Context, e Event) {
+	if !a.reportable(e.Status) {
+		return
+	}
+	select {
+	case <-a.done:
+		a.dropped.Add(1)
+		return
+	default:
+	}
+
+	if a.config.Policy == PolicyBlock {
+		var canceled <-chan struct{}
+		if c != nil {
+			canceled = c.Request().Context().Done()
+		}
+		select {
+		case a.queue <- e:
+		case <-a.done:
+			a.dropped.Add(1)
+		case <-canceled:
+			a.dropped.Add(1)
+		}
+		return
+	}
+
+	select {
+	case a.queue <- e:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full or
+// the reporter was closed.
+func (a *AsyncReporter) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Flush delivers the queued events and waits until they are reported.
+func (a *AsyncReporter) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case a.flush <- reply:
+	case <-a.stopped:
+		return ErrReporterClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting events, delivers the queued ones and waits for the
+// background goroutine to exit, e.g. on graceful shutdown.
+func (a *AsyncReporter) Close(ctx context.Context) error {
+	a.close.Do(func() {
+		close(a.done)
+	})
+	select {
+	case <-a.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *AsyncReporter) reportable(status int) bool {
+	if status >= a.config.MinStatus {
+		return true
+	}
+	for _, s := range a.config.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *AsyncReporter) run() {
+	defer close(a.stopped)
+
+	ticker := time.NewTicker(a.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, a.config.BatchSize)
+	deliver := func() error {
+		var err error
+		for len(batch) > 0 {
+			n := len(batch)
+			if n > a.config.BatchSize {
+				n = a.config.BatchSize
+			}
+			if e := a.deliver(batch[:n]); e != nil {
+				err = e
+			}
+			batch = batch[n:]
+		}
+		batch = make([]Event, 0, a.config.BatchSize)
+		return err
+	}
+	drain := func() {
+		for {
+			select {
+			case e := <-a.queue:
+				batch = append(batch, e)
+			default:
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case e := <-a.queue:
+			batch = append(batch, e)
+			if len(batch) >= a.config.BatchSize {
+				_ = deliver()
+			}
+		case <-ticker.C:
+			_ = deliver()
+		case reply := <-a.flush:
+			drain()
+			reply <- deliver()
+		case <-a.done:
+			drain()
+			_ = deliver()
+			return
+		}
+	}
+}
+
+func (a *AsyncReporter) deliver(events []Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.Timeout)
+	defer cancel()
+	err := a.reporter.Report(ctx, events)
+	if err != nil && a.config.OnError != nil {
+		a.config.OnError(err)
+	}
+	return err
+}
+
+// NewAsyncReporter returns an AsyncReporter delivering to r. Close it on
+// shutdown to flush the queued events.
+func NewAsyncReporter(r Reporter, config ...ReporterConfig) *AsyncReporter {
+	cfg := ReporterConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.MinStatus == 0 {
+		cfg.MinStatus = http.StatusInternalServerError
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	a := &AsyncReporter{
+		reporter: r,
+		config:   cfg,
+		queue:    make(chan Event, cfg.QueueSize),
+		flush:    make(chan chan error),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+type HTTPReporterConfig struct {
+	// URL receives a POST with the JSON array of events.
+	URL    string
+	Header http.Header
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+type httpReporter struct {
+	config HTTPReporterConfig
+}
+
+// Report implements Reporter.
+func (h *httpReporter) Report(ctx context.Context, events []Event) error {
+	b, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.config.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	for k, v := range h.config.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	res, err := h.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("echoerror: report to %s failed with status %d", h.config.URL, res.StatusCode)
+	}
+	return nil
+}
+
+// NewHTTPReporter returns a Reporter that posts each batch as a JSON array.
+func NewHTTPReporter(config HTTPReporterConfig) Reporter {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	return &httpReporter{config: config}
+}
+
+type fileReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Report implements Reporter.
+func (f *fileReporter) Report(ctx context.Context, events []Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	enc := json.NewEncoder(f.w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewFileReporter returns a Reporter that writes events to w as JSON lines.
+func NewFileReporter(w io.Writer) Reporter {
+	return &fileReporter{w: w}
+}
@@ -0,0 +1,153 @@
+package echoerror_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncReporterHTTP(t *testing.T) {
+	mu := sync.Mutex{}
+	batches := [][]echoerror.Event{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events := []echoerror.Event{}
+		_ = json.NewDecoder(r.Body).Decode(&events)
+		mu.Lock()
+		batches = append(batches, events)
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	reporter := echoerror.NewAsyncReporter(echoerror.NewHTTPReporter(echoerror.HTTPReporterConfig{
+		URL:    server.URL,
+		Header: http.Header{"Authorization": []string{"Bearer token"}},
+	}), echoerror.ReporterConfig{
+		Statuses:      []int{http.StatusTooManyRequests},
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	})
+
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Observers: []echoerror.Observer{reporter},
+	})
+	for _, err := range []error{
+		goerror.NewInternalServerError(),
+		goerror.NewNotFound(),
+		goerror.NewTooManyRequests(),
+		goerror.NewBadGateway(),
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		c := app.NewContext(req, httptest.NewRecorder())
+		_ = res.With(c).Response(err)
+	}
+
+	if err := reporter.Flush(context.Background()); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	count := 0
+	for _, b := range batches {
+		if len(b) > 2 {
+			t.Error("Error", len(b))
+		}
+		for _, e := range b {
+			if e.Status == http.StatusNotFound {
+				t.Error("Error", e)
+			}
+			count++
+		}
+	}
+	if count != 3 {
+		t.Error("Error", count)
+	}
+}
+
+func TestAsyncReporterNonJSONRenderer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := echoerror.NewAsyncReporter(echoerror.NewFileReporter(buf), echoerror.ReporterConfig{
+		FlushInterval: time.Hour,
+	})
+
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Observers: []echoerror.Observer{reporter},
+	})
+	app.POST("/graphql", func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewBadGateway())
+	}, echoerror.UseRenderer(echoerror.NewGraphQLRenderer()))
+	app.GET("/events", func(c echo.Context) error {
+		c.Response().WriteHeader(http.StatusOK)
+		return res.With(c).Response(goerror.NewInternalServerError())
+	}, echoerror.UseRenderer(echoerror.NewSSERenderer()))
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/graphql", nil))
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if err := reporter.Flush(context.Background()); err != nil {
+		t.Fatal("Error", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"status":502`) || !strings.Contains(lines[1], `"status":500`) {
+		t.Error("Error", buf.String())
+	}
+}
+
+func TestAsyncReporterDropAndClose(t *testing.T) {
+	release := make(chan struct{})
+	buf := &bytes.Buffer{}
+	file := echoerror.NewFileReporter(buf)
+	blocking := reporterFunc(func(ctx context.Context, events []echoerror.Event) error {
+		<-release
+		return file.Report(ctx, events)
+	})
+
+	reporter := echoerror.NewAsyncReporter(blocking, echoerror.ReporterConfig{
+		QueueSize:     1,
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+	})
+
+	for i := 0; i < 10; i++ {
+		reporter.Observe(nil, echoerror.Event{Status: http.StatusInternalServerError, Code: goerror.CodeInternalServerError})
+	}
+	if reporter.Dropped() == 0 {
+		t.Error("Error", reporter.Dropped())
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := reporter.Close(ctx); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if int(reporter.Dropped())+len(lines) != 10 {
+		t.Error("Error", reporter.Dropped(), len(lines))
+	}
+	if err := reporter.Flush(ctx); err != echoerror.ErrReporterClosed {
+		t.Error("Error", err)
+	}
+}
+
+type reporterFunc func(ctx context.Context, events []echoerror.Event) error
+
+// Report implements echoerror.Reporter.
+func (f reporterFunc) Report(ctx context.Context, events []echoerror.Event) error {
+	return f(ctx, events)
+}
@@ -4,16 +4,50 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/prongbang/goerror"
 	"net/http"
+	"strings"
 )
 
 type Config struct {
-	Custom *Custom
-	I18n   *I18n
+	Custom   *Custom
+	I18n     *I18n
+	Renderer Renderer
+	Envelope *Envelope
+	// Observers are notified of every error rendered by Response.
+	Observers []Observer
+	// Mappers translate errors that are not goerror types before rendering,
+	// e.g. StdlibMappers. The first non-nil result is rendered.
+	Mappers []Mapper
+	// CanceledStatus is rendered for requests canceled by the client.
+	// Defaults to StatusClientClosedRequest (499).
+	CanceledStatus int
+	// DeadlineStatus is rendered for deadlines and network timeouts, e.g.
+	// 503 when a timeout means the service is overloaded. Defaults to
+	// StatusGatewayTimeout (504).
+	DeadlineStatus int
+	// AbortOnCommitted aborts the connection when an error is returned after
+	// the response was committed, so clients do not take a truncated body for
+	// a complete one.
+	AbortOnCommitted bool
+	// Headers are set on every rendered error, replacing those set by the
+	// handler. When nil, DefaultHeaders are set on 4xx and 5xx errors only;
+	// an empty map sets none. An empty value removes the header.
+	Headers map[string]string
+	// ClassHeaders are applied over Headers by status class, e.g.
+	// {5: {"Retry-After": "30"}} for 5xx.
+	ClassHeaders map[int]map[string]string
 }
 
 type I18n struct {
 	Enabled  bool
 	Localize func(c echo.Context, code string) (string, error)
+	// LocalizeParams is used instead of Localize when set. It also receives
+	// the parameters of the error, see WithParams.
+	LocalizeParams func(c echo.Context, code string, params map[string]any) (string, error)
+	// Missing records the codes that failed to localize.
+	Missing *MissingTranslations
+	// Fallback is the message of errors that failed to localize. Defaults to
+	// the error code, so the message is never empty.
+	Fallback string
 }
 
 type Custom interface {
@@ -29,186 +63,431 @@ type HttpResponse interface {
 }
 
 type response struct {
-	Cus  *Custom
-	I18n *I18n
+	Config *Config
 }
 
 type httpResponse struct {
-	Ctx  echo.Context
-	Cus  *Custom
-	I18n *I18n
+	*Config
+	Ctx echo.Context
+	// defaultHeaders is set when the global Headers are nil, so errors get
+	// DefaultHeaders under the Headers of overrides.
+	defaultHeaders bool
 }
 
-// With implements Response.
+// With implements Response. A Config attached by Override is merged over the
+// global configuration.
 func (r *response) With(c echo.Context) HttpResponse {
+	cfg := r.Config
+	defaultHeaders := cfg.Headers == nil
+	if override, ok := c.Get(configContextKey).(*Config); ok {
+		cfg = cfg.merge(override)
+	}
 	return &httpResponse{
-		Ctx:  c,
-		Cus:  r.Cus,
-		I18n: r.I18n,
+		Config:         cfg,
+		Ctx:            c,
+		defaultHeaders: defaultHeaders,
 	}
 }
 
 // Response implements Response.
+// Nothing is written once the response is committed, e.g. by a streaming
+// handler; observers are still notified. A StreamRenderer writes into the
+// committed stream instead.
 func (s *httpResponse) Response(err error) error {
-	switch e := err.(type) {
+	var e error
+	err, params := splitParams(err)
+	rendered := s.mapError(err)
+	committed := s.Ctx.Response().Committed && !s.streaming()
+	if !committed {
+		if !s.streaming() {
+			s.setHeaders()
+		}
+		e = s.respond(rendered, params)
+	}
+	if len(s.Observers) > 0 {
+		event := newEvent(s.Ctx, err, rendered)
+		event.Committed = committed
+		if code, ok := s.statusCode(rendered); ok {
+			event.Status = code
+		}
+		for _, o := range s.Observers {
+			o.Observe(s.Ctx, event)
+		}
+	}
+	if committed && s.AbortOnCommitted {
+		panic(http.ErrAbortHandler)
+	}
+	return e
+}
+
+// mapError translates errors that are not goerror types before rendering with
+// the configured Mappers. Context cancellations and deadlines are always mapped.
+func (s *httpResponse) mapError(err error) error {
+	if _, ok := s.statusCode(err); ok {
+		return err
+	}
+	for _, m := range s.Mappers {
+		if mapped := m(err); mapped != nil {
+			return mapped
+		}
+	}
+	return mapContextError(err)
+}
+
+// statusCode returns the HTTP status code of a goerror type, a
+// ClientClosedRequest or a DeadlineExceeded.
+func (s *httpResponse) statusCode(err error) (int, bool) {
+	switch err.(type) {
+	case *ClientClosedRequest:
+		if s.CanceledStatus != 0 {
+			return s.CanceledStatus, true
+		}
+		return StatusClientClosedRequest, true
+	case *DeadlineExceeded:
+		if s.DeadlineStatus != 0 {
+			return s.DeadlineStatus, true
+		}
+		return http.StatusGatewayTimeout, true
+	}
+	return StatusCode(err)
+}
+
+// respond renders err with its message interpolated with params.
+func (s *httpResponse) respond(err error, params map[string]any) error {
+	if code, ok := s.statusCode(err); ok {
+		return s.render(code, interpolate(err, params))
+	}
+
+	// Other
+	if s.Custom != nil {
+		err = interpolate(s.localize(err, params), params)
+		return (*s.Custom).Response(s.context(), err)
+	}
+
+	// Default response
+	return s.render(http.StatusBadRequest, goerror.NewBadRequest())
+}
+
+// localize returns an error that has a code but no message with its localized
+// message. err itself is not changed.
+func (s *httpResponse) localize(err error, params map[string]any) error {
+	if s.I18n == nil || !s.I18n.Enabled || (s.I18n.Localize == nil && s.I18n.LocalizeParams == nil) {
+		return err
+	}
+	body, e1 := goerror.GetBody(err)
+	if e1 != nil || body.Code == "" || body.Message != "" {
+		return err
+	}
+
+	var localize string
+	var e2 error
+	if s.I18n.LocalizeParams != nil {
+		localize, e2 = s.I18n.LocalizeParams(s.Ctx, body.Code, params)
+	} else {
+		localize, e2 = s.I18n.Localize(s.Ctx, body.Code)
+	}
+	if e2 == nil && localize != "" {
+		s.setLanguage()
+		return withMessage(err, localize)
+	}
+
+	if s.I18n.Missing != nil {
+		s.I18n.Missing.Record(body.Code, s.requestedLocale())
+	}
+	fallback := s.I18n.Fallback
+	if fallback == "" {
+		fallback = body.Code
+	}
+	return withMessage(err, fallback)
+}
+
+// requestedLocale returns the locale reported with SetLocale, or the preferred
+// language of the request.
+func (s *httpResponse) requestedLocale() string {
+	if locale, ok := s.Ctx.Get(localeContextKey).(string); ok {
+		return locale
+	}
+	if locales := ParseAcceptLanguage(s.Ctx.Request().Header.Get(HeaderAcceptLanguage)); len(locales) > 0 {
+		return NormalizeLocale(locales[0])
+	}
+	return ""
+}
+
+// setLanguage tells caches that the body of a localized response depends on
+// the request language, and which language it is in.
+func (s *httpResponse) setLanguage() {
+	h := s.Ctx.Response().Header()
+	if locale, ok := s.Ctx.Get(localeContextKey).(string); ok && locale != "" {
+		h.Set(HeaderContentLanguage, locale)
+	}
+	addVary(h, HeaderAcceptLanguage)
+}
+
+// addVary adds values to the Vary header unless already listed.
+func addVary(h http.Header, values ...string) {
+	for _, v := range values {
+		listed := false
+		for _, line := range h.Values(echo.HeaderVary) {
+			for _, field := range strings.Split(line, ",") {
+				if strings.EqualFold(strings.TrimSpace(field), v) {
+					listed = true
+				}
+			}
+		}
+		if !listed {
+			h.Add(echo.HeaderVary, v)
+		}
+	}
+}
+
+// render writes err with the configured Renderer, or as plain JSON by default.
+// Responses that HTTP forbids a body for only get the status and headers.
+func (s *httpResponse) render(code int, err error) error {
+	r := s.renderer()
+	status := code
+	if sr, ok := r.(StatusRenderer); ok {
+		status = sr.Status(s.Ctx, code)
+	}
+	if !s.streaming() && Bodiless(s.Ctx.Request().Method, status) {
+		return s.Ctx.NoContent(status)
+	}
+	if r != nil {
+		return r.Render(s.Ctx, code, err)
+	}
+	return s.Ctx.JSON(code, err)
+}
+
+// renderer returns the Renderer, falling back to the Envelope.
+func (s *httpResponse) renderer() Renderer {
+	if s.Renderer != nil {
+		return s.Renderer
+	}
+	if s.Envelope != nil {
+		return s.Envelope
+	}
+	return nil
+}
+
+// streaming reports whether the Renderer writes into an open stream.
+func (s *httpResponse) streaming() bool {
+	sr, ok := s.renderer().(StreamRenderer)
+	return ok && sr.Streaming()
+}
+
+// context returns the echo.Context handed to Custom, routing its JSON errors
+// through render.
+func (s *httpResponse) context() echo.Context {
+	return &renderContext{Context: s.Ctx, render: s.render}
+}
+
+// Bodiless reports whether HTTP forbids a response body for the request
+// method and status code.
+func Bodiless(method string, code int) bool {
+	switch code {
+	case http.StatusNoContent, http.StatusResetContent, http.StatusNotModified:
+		return true
+	}
+	return method == http.MethodHead
+}
+
+// StatusCode returns the HTTP status code of a goerror type. It reports false
+// for any other error.
+func StatusCode(err error) (int, bool) {
+	switch err.(type) {
 	// Information
 	case *goerror.Continue:
-		return s.Ctx.JSON(http.StatusContinue, e)
+		return http.StatusContinue, true
 	case *goerror.SwitchingProtocols:
-		return s.Ctx.JSON(http.StatusSwitchingProtocols, e)
+		return http.StatusSwitchingProtocols, true
 	case *goerror.Processing:
-		return s.Ctx.JSON(http.StatusProcessing, e)
+		return http.StatusProcessing, true
 	case *goerror.EarlyHints:
-		return s.Ctx.JSON(http.StatusEarlyHints, e)
+		return http.StatusEarlyHints, true
 
 	// Successful
 	case *goerror.OK:
-		return s.Ctx.JSON(http.StatusOK, e)
+		return http.StatusOK, true
 	case *goerror.Created:
-		return s.Ctx.JSON(http.StatusCreated, e)
+		return http.StatusCreated, true
 	case *goerror.Accepted:
-		return s.Ctx.JSON(http.StatusAccepted, e)
+		return http.StatusAccepted, true
 	case *goerror.NonAuthoritativeInformation:
-		return s.Ctx.JSON(http.StatusNonAuthoritativeInfo, e)
+		return http.StatusNonAuthoritativeInfo, true
 	case *goerror.NoContent:
-		return s.Ctx.JSON(http.StatusNoContent, e)
+		return http.StatusNoContent, true
 	case *goerror.ResetContent:
-		return s.Ctx.JSON(http.StatusResetContent, e)
+		return http.StatusResetContent, true
 	case *goerror.PartialContent:
-		return s.Ctx.JSON(http.StatusPartialContent, e)
+		return http.StatusPartialContent, true
 	case *goerror.MultiStatus:
-		return s.Ctx.JSON(http.StatusMultiStatus, e)
+		return http.StatusMultiStatus, true
 	case *goerror.AlreadyReported:
-		return s.Ctx.JSON(http.StatusAlreadyReported, e)
+		return http.StatusAlreadyReported, true
 	case *goerror.IMUsed:
-		return s.Ctx.JSON(http.StatusIMUsed, e)
+		return http.StatusIMUsed, true
 
 	// Redirection
 	case *goerror.MultipleChoices:
-		return s.Ctx.JSON(http.StatusMultipleChoices, e)
+		return http.StatusMultipleChoices, true
 	case *goerror.MovedPermanently:
-		return s.Ctx.JSON(http.StatusMovedPermanently, e)
+		return http.StatusMovedPermanently, true
 	case *goerror.Found:
-		return s.Ctx.JSON(http.StatusFound, e)
+		return http.StatusFound, true
 	case *goerror.SeeOther:
-		return s.Ctx.JSON(http.StatusSeeOther, e)
+		return http.StatusSeeOther, true
 	case *goerror.NotModified:
-		return s.Ctx.JSON(http.StatusNotModified, e)
+		return http.StatusNotModified, true
 	case *goerror.UseProxy:
-		return s.Ctx.JSON(http.StatusUseProxy, e)
+		return http.StatusUseProxy, true
 	case *goerror.TemporaryRedirect:
-		return s.Ctx.JSON(http.StatusTemporaryRedirect, e)
+		return http.StatusTemporaryRedirect, true
 	case *goerror.PermanentRedirect:
-		return s.Ctx.JSON(http.StatusPermanentRedirect, e)
+		return http.StatusPermanentRedirect, true
 
 	// Client error
 	case *goerror.BadRequest:
-		return s.Ctx.JSON(http.StatusBadRequest, e)
+		return http.StatusBadRequest, true
 	case *goerror.Unauthorized:
-		return s.Ctx.JSON(http.StatusUnauthorized, e)
+		return http.StatusUnauthorized, true
 	case *goerror.PaymentRequired:
-		return s.Ctx.JSON(http.StatusPaymentRequired, e)
+		return http.StatusPaymentRequired, true
 	case *goerror.Forbidden:
-		return s.Ctx.JSON(http.StatusForbidden, e)
+		return http.StatusForbidden, true
 	case *goerror.NotFound:
-		return s.Ctx.JSON(http.StatusNotFound, e)
+		return http.StatusNotFound, true
 	case *goerror.MethodNotAllowed:
-		return s.Ctx.JSON(http.StatusMethodNotAllowed, e)
+		return http.StatusMethodNotAllowed, true
 	case *goerror.NotAcceptable:
-		return s.Ctx.JSON(http.StatusNotAcceptable, e)
+		return http.StatusNotAcceptable, true
 	case *goerror.ProxyAuthRequired:
-		return s.Ctx.JSON(http.StatusProxyAuthRequired, e)
+		return http.StatusProxyAuthRequired, true
 	case *goerror.RequestTimeout:
-		return s.Ctx.JSON(http.StatusRequestTimeout, e)
+		return http.StatusRequestTimeout, true
 	case *goerror.Conflict:
-		return s.Ctx.JSON(http.StatusConflict, e)
+		return http.StatusConflict, true
 	case *goerror.Gone:
-		return s.Ctx.JSON(http.StatusGone, e)
+		return http.StatusGone, true
 	case *goerror.LengthRequired:
-		return s.Ctx.JSON(http.StatusLengthRequired, e)
+		return http.StatusLengthRequired, true
 	case *goerror.PreconditionFailed:
-		return s.Ctx.JSON(http.StatusPreconditionFailed, e)
+		return http.StatusPreconditionFailed, true
 	case *goerror.RequestEntityTooLarge:
-		return s.Ctx.JSON(http.StatusRequestEntityTooLarge, e)
+		return http.StatusRequestEntityTooLarge, true
 	case *goerror.RequestURITooLong:
-		return s.Ctx.JSON(http.StatusRequestURITooLong, e)
+		return http.StatusRequestURITooLong, true
 	case *goerror.UnsupportedMediaType:
-		return s.Ctx.JSON(http.StatusUnsupportedMediaType, e)
+		return http.StatusUnsupportedMediaType, true
 	case *goerror.RequestedRangeNotSatisfiable:
-		return s.Ctx.JSON(http.StatusRequestedRangeNotSatisfiable, e)
+		return http.StatusRequestedRangeNotSatisfiable, true
 	case *goerror.ExpectationFailed:
-		return s.Ctx.JSON(http.StatusExpectationFailed, e)
+		return http.StatusExpectationFailed, true
 	case *goerror.Teapot:
-		return s.Ctx.JSON(http.StatusTeapot, e)
+		return http.StatusTeapot, true
 	case *goerror.MisdirectedRequest:
-		return s.Ctx.JSON(http.StatusMisdirectedRequest, e)
+		return http.StatusMisdirectedRequest, true
 	case *goerror.UnprocessableEntity:
-		return s.Ctx.JSON(http.StatusUnprocessableEntity, e)
+		return http.StatusUnprocessableEntity, true
 	case *goerror.Locked:
-		return s.Ctx.JSON(http.StatusLocked, e)
+		return http.StatusLocked, true
 	case *goerror.FailedDependency:
-		return s.Ctx.JSON(http.StatusFailedDependency, e)
+		return http.StatusFailedDependency, true
 	case *goerror.TooEarly:
-		return s.Ctx.JSON(http.StatusTooEarly, e)
+		return http.StatusTooEarly, true
 	case *goerror.UpgradeRequired:
-		return s.Ctx.JSON(http.StatusUpgradeRequired, e)
+		return http.StatusUpgradeRequired, true
 	case *goerror.PreconditionRequired:
-		return s.Ctx.JSON(http.StatusPreconditionRequired, e)
+		return http.StatusPreconditionRequired, true
 	case *goerror.TooManyRequests:
-		return s.Ctx.JSON(http.StatusTooManyRequests, e)
+		return http.StatusTooManyRequests, true
 	case *goerror.RequestHeaderFieldsTooLarge:
-		return s.Ctx.JSON(http.StatusRequestHeaderFieldsTooLarge, e)
+		return http.StatusRequestHeaderFieldsTooLarge, true
 	case *goerror.UnavailableForLegalReasons:
-		return s.Ctx.JSON(http.StatusUnavailableForLegalReasons, e)
+		return http.StatusUnavailableForLegalReasons, true
 
 	// Server error
 	case *goerror.InternalServerError:
-		return s.Ctx.JSON(http.StatusInternalServerError, e)
+		return http.StatusInternalServerError, true
 	case *goerror.NotImplemented:
-		return s.Ctx.JSON(http.StatusNotImplemented, e)
+		return http.StatusNotImplemented, true
 	case *goerror.BadGateway:
-		return s.Ctx.JSON(http.StatusBadGateway, e)
+		return http.StatusBadGateway, true
 	case *goerror.ServiceUnavailable:
-		return s.Ctx.JSON(http.StatusServiceUnavailable, e)
+		return http.StatusServiceUnavailable, true
 	case *goerror.GatewayTimeout:
-		return s.Ctx.JSON(http.StatusGatewayTimeout, e)
+		return http.StatusGatewayTimeout, true
 	case *goerror.HTTPVersionNotSupported:
-		return s.Ctx.JSON(http.StatusHTTPVersionNotSupported, e)
+		return http.StatusHTTPVersionNotSupported, true
 	case *goerror.VariantAlsoNegotiates:
-		return s.Ctx.JSON(http.StatusVariantAlsoNegotiates, e)
+		return http.StatusVariantAlsoNegotiates, true
 	case *goerror.InsufficientStorage:
-		return s.Ctx.JSON(http.StatusInsufficientStorage, e)
+		return http.StatusInsufficientStorage, true
 	case *goerror.LoopDetected:
-		return s.Ctx.JSON(http.StatusLoopDetected, e)
+		return http.StatusLoopDetected, true
 	case *goerror.NotExtended:
-		return s.Ctx.JSON(http.StatusNotExtended, e)
+		return http.StatusNotExtended, true
 	case *goerror.NetworkAuthenticationRequired:
-		return s.Ctx.JSON(http.StatusNetworkAuthenticationRequired, e)
+		return http.StatusNetworkAuthenticationRequired, true
+	}
+	return 0, false
+}
 
-	// Other
-	default:
-		if s.Cus != nil {
-			if s.I18n != nil && s.I18n.Enabled && s.I18n.Localize != nil {
-				body, e1 := goerror.GetBody(err)
-				if e1 == nil && body.Code != "" && body.Message == "" {
-					if localize, e2 := s.I18n.Localize(s.Ctx, body.Code); e2 == nil {
-						goerror.SetMessage(err, localize)
-					}
-				}
-			}
-			return (*s.Cus).Response(s.Ctx, err)
+// merge returns a copy of c with the non-nil fields of o applied. An Envelope
+// set without a Renderer replaces the Renderer of c, so the override decides
+// the output format. Observers of o are added to those of c, and Mappers of o
+// run before those of c. Headers and ClassHeaders of o are applied over those
+// of c by name.
+func (c *Config) merge(o *Config) *Config {
+	m := *c
+	if o.Custom != nil {
+		m.Custom = o.Custom
+	}
+	if o.I18n != nil {
+		m.I18n = o.I18n
+	}
+	if o.Renderer != nil {
+		m.Renderer = o.Renderer
+	}
+	if o.Envelope != nil {
+		m.Envelope = o.Envelope
+		if o.Renderer == nil {
+			m.Renderer = nil
+		}
+	}
+	if len(o.Observers) > 0 {
+		m.Observers = append(append([]Observer{}, c.Observers...), o.Observers...)
+	}
+	if len(o.Mappers) > 0 {
+		m.Mappers = append(append([]Mapper{}, o.Mappers...), c.Mappers...)
+	}
+	if o.CanceledStatus != 0 {
+		m.CanceledStatus = o.CanceledStatus
+	}
+	if o.DeadlineStatus != 0 {
+		m.DeadlineStatus = o.DeadlineStatus
+	}
+	if o.AbortOnCommitted {
+		m.AbortOnCommitted = true
+	}
+	if o.Headers != nil {
+		m.Headers = mergeHeaders(c.Headers, o.Headers)
+	}
+	if len(o.ClassHeaders) > 0 {
+		m.ClassHeaders = map[int]map[string]string{}
+		for class, h := range c.ClassHeaders {
+			m.ClassHeaders[class] = h
+		}
+		for class, h := range o.ClassHeaders {
+			m.ClassHeaders[class] = mergeHeaders(c.ClassHeaders[class], h)
 		}
-		// Default response
-		return s.Ctx.JSON(http.StatusBadRequest, goerror.NewBadRequest())
 	}
+	return &m
 }
 
 func New(config ...*Config) Response {
-	resp := &response{}
-	if len(config) > 0 {
-		cfg := config[0]
-		resp.Cus = cfg.Custom
-		resp.I18n = cfg.I18n
+	resp := &response{Config: &Config{}}
+	if len(config) > 0 && config[0] != nil {
+		cfg := *config[0]
+		resp.Config = &cfg
 	}
 	return resp
 }
@@ -1245,3 +1245,42 @@ func TestNewNetworkAuthenticationRequired(t *testing.T) {
 		t.Error("Error", resp.Code)
 	}
 }
+
+func TestNoContentWithoutBody(t *testing.T) {
+	app := echo.New()
+
+	for _, err := range []error{goerror.NewNoContent(), goerror.NewResetContent(), goerror.NewNotModified()} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := app.NewContext(req, resp)
+		c.Response().Header().Set("ETag", `"v1"`)
+
+		_ = response.With(c).Response(err)
+
+		code, _ := echoerror.StatusCode(err)
+		if resp.Code != code || resp.Body.Len() != 0 || resp.Header().Get("ETag") != `"v1"` {
+			t.Error("Error", resp.Code, resp.Body.String())
+		}
+	}
+}
+
+func TestHeadWithoutBody(t *testing.T) {
+	app := echo.New()
+
+	customResp := NewCustomResponse()
+	res := echoerror.New(&echoerror.Config{
+		Custom: &customResp,
+	})
+
+	for _, err := range []error{goerror.NewNotFound(), NewCustomError()} {
+		req := httptest.NewRequest(http.MethodHead, "/", nil)
+		resp := httptest.NewRecorder()
+		c := app.NewContext(req, resp)
+
+		_ = res.With(c).Response(err)
+
+		if resp.Code < http.StatusBadRequest || resp.Body.Len() != 0 {
+			t.Error("Error", resp.Code, resp.Body.String())
+		}
+	}
+}
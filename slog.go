@@ -0,0 +1,124 @@
+package echoerror
+
+import (
+	"context"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"sync"
+)
+
+type SlogConfig struct {
+	// Level chooses the log level by status. Defaults to Error for 5xx, Warn
+	// for 4xx and Info otherwise.
+	Level func(status int) slog.Level
+	// Sample logs one of every N errors of a code, e.g. {"CLE004": 100}.
+	Sample map[string]int
+	// Message is the log message. Defaults to "error response".
+	Message string
+}
+
+type slogObserver struct {
+	logger *slog.Logger
+	config SlogConfig
+	mu     sync.Mutex
+	seen   map[string]int
+}
+
+// Observe implements Observer.
+func (s *slogObserver) Observe(c echo.Context, e Event) {
+	if !s.sampled(e.Code) {
+		return
+	}
+
+	ctx := context.Background()
+	if c != nil {
+		ctx = c.Request().Context()
+	}
+	level := s.config.Level(e.Status)
+	if !s.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.Int("status", e.Status),
+		slog.String("code", e.Code),
+		slog.String("message", e.Message),
+		slog.String("method", e.Method),
+		slog.String("route", e.Route),
+		slog.String("path", e.Path),
+		slog.String("fingerprint", e.Fingerprint),
+	}
+	if e.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", e.RequestID))
+	}
+	if chain := ErrorChain(e.Err); len(chain) > 0 {
+		attrs = append(attrs, slog.Any("error_chain", chain))
+	}
+	s.logger.LogAttrs(ctx, level, s.config.Message, attrs...)
+}
+
+// sampled reports whether the next error of code is logged.
+func (s *slogObserver) sampled(code string) bool {
+	n := s.config.Sample[code]
+	if n <= 1 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := s.seen[code]
+	s.seen[code] = (count + 1) % n
+	return count == 0
+}
+
+// ErrorChain returns the messages of err and the errors it wraps, depth first.
+func ErrorChain(err error) []string {
+	var chain []string
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		chain = append(chain, err.Error())
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				walk(e)
+			}
+		default:
+			walk(errors.Unwrap(err))
+		}
+	}
+	walk(err)
+	return chain
+}
+
+func defaultSlogLevel(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	}
+	return slog.LevelInfo
+}
+
+// NewSlogObserver returns an Observer that logs every rendered error to logger
+// with structured attributes.
+func NewSlogObserver(logger *slog.Logger, config ...SlogConfig) Observer {
+	cfg := SlogConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Level == nil {
+		cfg.Level = defaultSlogLevel
+	}
+	if cfg.Message == "" {
+		cfg.Message = "error response"
+	}
+	return &slogObserver{
+		logger: logger,
+		config: cfg,
+		seen:   map[string]int{},
+	}
+}
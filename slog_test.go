@@ -0,0 +1,84 @@
+package echoerror_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSlogObserver(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Observers: []echoerror.Observer{echoerror.NewSlogObserver(logger, echoerror.SlogConfig{
+			Sample: map[string]int{goerror.CodeNotFound: 3},
+		})},
+	})
+
+	errs := []error{goerror.NewInternalServerError()}
+	for i := 0; i < 6; i++ {
+		errs = append(errs, goerror.NewNotFound())
+	}
+	for _, err := range errs {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderXRequestID, "req-1")
+		c := app.NewContext(req, httptest.NewRecorder())
+		_ = res.With(c).Response(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatal("Error", buf.String())
+	}
+
+	first := map[string]any{}
+	_ = json.Unmarshal([]byte(lines[0]), &first)
+	if first["level"] != "ERROR" || first["status"] != float64(http.StatusInternalServerError) || first["request_id"] != "req-1" {
+		t.Error("Error", lines[0])
+	}
+	second := map[string]any{}
+	_ = json.Unmarshal([]byte(lines[1]), &second)
+	if second["level"] != "WARN" || second["code"] != goerror.CodeNotFound {
+		t.Error("Error", lines[1])
+	}
+}
+
+func TestSlogObserverGraphQL(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Renderer:  echoerror.NewGraphQLRenderer(),
+		Observers: []echoerror.Observer{echoerror.NewSlogObserver(logger, echoerror.SlogConfig{})},
+	})
+	c := app.NewContext(httptest.NewRequest(http.MethodPost, "/graphql", nil), httptest.NewRecorder())
+	_ = res.With(c).Response(goerror.NewInternalServerError())
+
+	actual := map[string]any{}
+	_ = json.Unmarshal(buf.Bytes(), &actual)
+	if actual["level"] != "ERROR" || actual["status"] != float64(http.StatusInternalServerError) {
+		t.Error("Error", buf.String())
+	}
+}
+
+func TestErrorChain(t *testing.T) {
+	base := errors.New("connection refused")
+	err := fmt.Errorf("load user: %w", errors.Join(base, errors.New("retry failed")))
+
+	chain := echoerror.ErrorChain(err)
+	if len(chain) != 4 || chain[2] != "connection refused" || chain[3] != "retry failed" {
+		t.Error("Error", chain)
+	}
+}
@@ -0,0 +1,131 @@
+package echoerror
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// WebSocket close codes of RFC 6455 used for errors.
+const (
+	CloseNormalClosure   = 1000
+	CloseUnsupportedData = 1003
+	CloseInvalidPayload  = 1007
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	CloseInternalErr     = 1011
+	CloseTryAgainLater   = 1013
+)
+
+// StreamRenderer is implemented by Renderers that write into an open stream,
+// such as SSE or WebSocket. They render even after the response is committed.
+type StreamRenderer interface {
+	Renderer
+	Streaming() bool
+}
+
+// streamBody returns the body of err with its status, as sent in stream frames.
+func streamBody(code int, err error) map[string]any {
+	body := (&Envelope{}).Wrap(err)
+	body["status"] = code
+	return body
+}
+
+type sseRenderer struct {
+	Event string
+}
+
+// Render implements Renderer. The error is written as a Server-Sent Event,
+// opening the stream first if the handler has not done so.
+func (s *sseRenderer) Render(c echo.Context, code int, err error) error {
+	b, e := json.Marshal(streamBody(code, err))
+	if e != nil {
+		return e
+	}
+
+	res := c.Response()
+	if !res.Committed {
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set(echo.HeaderCacheControl, "no-cache")
+		res.WriteHeader(http.StatusOK)
+	}
+	if _, e := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", s.Event, b); e != nil {
+		return e
+	}
+	res.Flush()
+	return nil
+}
+
+// Streaming implements StreamRenderer.
+func (s *sseRenderer) Streaming() bool {
+	return true
+}
+
+// NewSSERenderer returns a Renderer that writes errors as an "error" event
+// of a Server-Sent Events stream.
+func NewSSERenderer() Renderer {
+	return &sseRenderer{Event: "error"}
+}
+
+// WebSocketSender writes a text message to the WebSocket connection of c and
+// closes it with closeCode.
+type WebSocketSender func(c echo.Context, message []byte, closeCode int) error
+
+type webSocketRenderer struct {
+	send       WebSocketSender
+	closeCodes map[int]int
+}
+
+// Render implements Renderer.
+func (w *webSocketRenderer) Render(c echo.Context, code int, err error) error {
+	body := streamBody(code, err)
+	body["type"] = "error"
+	b, e := json.Marshal(body)
+	if e != nil {
+		return e
+	}
+
+	closeCode, ok := w.closeCodes[code]
+	if !ok {
+		closeCode = WebSocketCloseCode(code)
+	}
+	return w.send(c, b, closeCode)
+}
+
+// Streaming implements StreamRenderer.
+func (w *webSocketRenderer) Streaming() bool {
+	return true
+}
+
+// WebSocketCloseCode maps an HTTP status code to a WebSocket close code.
+func WebSocketCloseCode(status int) int {
+	switch status {
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests:
+		return CloseTryAgainLater
+	case http.StatusRequestEntityTooLarge:
+		return CloseMessageTooBig
+	case http.StatusUnsupportedMediaType:
+		return CloseUnsupportedData
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return CloseInvalidPayload
+	}
+	switch {
+	case status >= 500:
+		return CloseInternalErr
+	case status >= 400:
+		return ClosePolicyViolation
+	}
+	return CloseNormalClosure
+}
+
+// NewWebSocketRenderer returns a Renderer that sends errors as a JSON message
+// followed by a close frame through send. closeCodes overrides the close code
+// of single status codes.
+func NewWebSocketRenderer(send WebSocketSender, closeCodes ...map[int]int) Renderer {
+	w := &webSocketRenderer{send: send}
+	if len(closeCodes) > 0 {
+		w.closeCodes = closeCodes[0]
+	}
+	return w
+}
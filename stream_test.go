@@ -0,0 +1,82 @@
+package echoerror_test
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSSERenderer(t *testing.T) {
+	app := echo.New()
+	res := echoerror.New()
+
+	app.GET("/events", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		c.Response().WriteHeader(http.StatusOK)
+		_, _ = c.Response().Write([]byte("data: tick\n\n"))
+		c.Response().Flush()
+		return res.With(c).Response(goerror.NewServiceUnavailable())
+	}, echoerror.UseRenderer(echoerror.NewSSERenderer()))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	frames := strings.Split(strings.TrimSpace(resp.Body.String()), "\n\n")
+	if resp.Code != http.StatusOK || len(frames) != 2 {
+		t.Fatal("Error", resp.Code, resp.Body.String())
+	}
+	lines := strings.Split(frames[1], "\n")
+	if lines[0] != "event: error" || !strings.HasPrefix(lines[1], "data: ") {
+		t.Fatal("Error", frames[1])
+	}
+	body := map[string]any{}
+	_ = json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &body)
+	if body["status"] != float64(http.StatusServiceUnavailable) || body["code"] != goerror.CodeServiceUnavailable {
+		t.Error("Error", lines[1])
+	}
+}
+
+func TestWebSocketRenderer(t *testing.T) {
+	var message []byte
+	var closeCode int
+	send := func(c echo.Context, m []byte, code int) error {
+		message = m
+		closeCode = code
+		return nil
+	}
+
+	app := echo.New()
+	res := echoerror.New(&echoerror.Config{
+		Renderer: echoerror.NewWebSocketRenderer(send, map[int]int{http.StatusNotFound: 4404}),
+	})
+
+	cases := []struct {
+		err       error
+		closeCode int
+	}{
+		{goerror.NewInternalServerError(), echoerror.CloseInternalErr},
+		{goerror.NewServiceUnavailable(), echoerror.CloseTryAgainLater},
+		{goerror.NewForbidden(), echoerror.ClosePolicyViolation},
+		{goerror.NewBadRequest(), echoerror.CloseInvalidPayload},
+		{goerror.NewNotFound(), 4404},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		c := app.NewContext(req, httptest.NewRecorder())
+		c.Response().WriteHeader(http.StatusSwitchingProtocols)
+
+		_ = res.With(c).Response(tc.err)
+
+		body := map[string]any{}
+		_ = json.Unmarshal(message, &body)
+		if closeCode != tc.closeCode || body["type"] != "error" || body["message"] != tc.err.Error() {
+			t.Error("Error", closeCode, string(message))
+		}
+	}
+}
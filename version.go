@@ -0,0 +1,151 @@
+package echoerror
+
+import (
+	"github.com/labstack/echo/v4"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+)
+
+// Format is a registered version of the error response format.
+type Format struct {
+	// Version names the format, e.g. "1" or "2".
+	Version string
+	// Renderer writes the format. Errors are written as plain JSON when nil.
+	Renderer Renderer
+	// Deprecated announces with the Deprecation header (RFC 9745) that the
+	// format is deprecated since the given time.
+	Deprecated time.Time
+	// Sunset announces with the Sunset header (RFC 8594) when the format
+	// will be removed.
+	Sunset time.Time
+	// Link documents the deprecation, sent as a Link with rel="deprecation".
+	Link string
+}
+
+type VersionConfig struct {
+	// Formats are the versions a request can select.
+	Formats []Format
+	// Default is the version of requests that select none or an unknown one.
+	// Defaults to the first format.
+	Default string
+	// Header names a request header selecting the version, e.g.
+	// "X-Error-Version". Skipped when empty.
+	Header string
+	// AcceptParam names the media type parameter of Accept selecting the
+	// version, as in "application/json; version=2". Defaults to "version".
+	AcceptParam string
+	// PathPrefix selects the version by the first path segment, "2" or "v2"
+	// as in "/v2/users".
+	PathPrefix bool
+}
+
+type versionRenderer struct {
+	config  VersionConfig
+	formats map[string]*Format
+}
+
+// Render implements Renderer.
+func (v *versionRenderer) Render(c echo.Context, code int, err error) error {
+	h := c.Response().Header()
+	vary := []string{echo.HeaderAccept}
+	if v.config.Header != "" {
+		vary = append(vary, v.config.Header)
+	}
+	addVary(h, vary...)
+
+	f := v.format(c)
+	if !f.Deprecated.IsZero() {
+		h.Set(HeaderDeprecation, "@"+strconv.FormatInt(f.Deprecated.Unix(), 10))
+	}
+	if !f.Sunset.IsZero() {
+		h.Set(HeaderSunset, f.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if f.Link != "" {
+		h.Add("Link", "<"+f.Link+`>; rel="deprecation"`)
+	}
+
+	if f.Renderer != nil {
+		return f.Renderer.Render(c, code, err)
+	}
+	return c.JSON(code, err)
+}
+
+// Status implements StatusRenderer with the status written by the Renderer of
+// the selected format.
+func (v *versionRenderer) Status(c echo.Context, code int) int {
+	if sr, ok := v.format(c).Renderer.(StatusRenderer); ok {
+		return sr.Status(c, code)
+	}
+	return code
+}
+
+// format returns the format selected by the header, the Accept parameter or
+// the path prefix of the request, in this order, or the default format.
+func (v *versionRenderer) format(c echo.Context) *Format {
+	req := c.Request()
+	if v.config.Header != "" {
+		if f, ok := v.lookup(strings.TrimSpace(req.Header.Get(v.config.Header))); ok {
+			return f
+		}
+	}
+	for _, accept := range strings.Split(req.Header.Get(echo.HeaderAccept), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if f, ok := v.lookup(params[v.config.AcceptParam]); ok {
+			return f
+		}
+	}
+	if v.config.PathPrefix {
+		segment, _, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+		if f, ok := v.lookup(segment); ok {
+			return f
+		}
+		if f, ok := v.lookup(strings.TrimPrefix(segment, "v")); ok {
+			return f
+		}
+	}
+	return v.formats[v.config.Default]
+}
+
+func (v *versionRenderer) lookup(version string) (*Format, bool) {
+	if version == "" {
+		return nil, false
+	}
+	f, ok := v.formats[version]
+	return f, ok
+}
+
+// NewVersionedRenderer returns a Renderer that writes each error in the
+// format version selected by the request, and announces the deprecation of
+// old formats.
+func NewVersionedRenderer(config VersionConfig) Renderer {
+	if config.AcceptParam == "" {
+		config.AcceptParam = "version"
+	}
+	if config.Default == "" && len(config.Formats) > 0 {
+		config.Default = config.Formats[0].Version
+	}
+
+	formats := map[string]*Format{}
+	for _, f := range config.Formats {
+		f := f
+		formats[f.Version] = &f
+	}
+	if _, ok := formats[config.Default]; !ok {
+		formats[config.Default] = &Format{Version: config.Default}
+	}
+	return &versionRenderer{
+		config:  config,
+		formats: formats,
+	}
+}
@@ -0,0 +1,75 @@
+package echoerror_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prongbang/echoerror"
+	"github.com/prongbang/goerror"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVersionedRenderer(t *testing.T) {
+	sunset := time.Date(2027, time.October, 1, 0, 0, 0, 0, time.UTC)
+	res := echoerror.New(&echoerror.Config{
+		Renderer: echoerror.NewVersionedRenderer(echoerror.VersionConfig{
+			Formats: []echoerror.Format{
+				{Version: "1", Deprecated: time.Unix(1790000000, 0), Sunset: sunset, Link: "https://example.com/errors/v2"},
+				{Version: "2", Renderer: echoerror.NewProblemRenderer("")},
+			},
+			Default:    "2",
+			Header:     "X-Error-Version",
+			PathPrefix: true,
+		}),
+	})
+	app := echo.New()
+	app.GET("/*", func(c echo.Context) error {
+		return res.With(c).Response(goerror.NewNotFound())
+	})
+
+	cases := []struct {
+		path        string
+		header      string
+		accept      string
+		contentType string
+	}{
+		{path: "/users", contentType: echoerror.MIMEApplicationProblemJSON},
+		{path: "/users", header: "1", contentType: echo.MIMEApplicationJSON},
+		{path: "/users", header: "9", contentType: echoerror.MIMEApplicationProblemJSON},
+		{path: "/users", accept: "text/html, application/json; version=1", contentType: echo.MIMEApplicationJSON},
+		{path: "/v1/users", contentType: echo.MIMEApplicationJSON},
+		{path: "/v1/users", header: "2", contentType: echoerror.MIMEApplicationProblemJSON},
+		{path: "/v1/users", accept: "application/json;version=2", contentType: echoerror.MIMEApplicationProblemJSON},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.header != "" {
+			req.Header.Set("X-Error-Version", tc.header)
+		}
+		if tc.accept != "" {
+			req.Header.Set(echo.HeaderAccept, tc.accept)
+		}
+		resp := httptest.NewRecorder()
+		app.ServeHTTP(resp, req)
+
+		h := resp.Header()
+		if resp.Code != http.StatusNotFound || !strings.HasPrefix(h.Get(echo.HeaderContentType), tc.contentType) {
+			t.Error("Error", tc, resp.Code, h)
+		}
+		if strings.Join(h.Values(echo.HeaderVary), ", ") != "Accept, X-Error-Version" {
+			t.Error("Error", tc, h)
+		}
+		deprecated := tc.contentType == echo.MIMEApplicationJSON
+		if deprecated != (h.Get(echoerror.HeaderDeprecation) == "@1790000000") {
+			t.Error("Error", tc, h)
+		}
+		if deprecated != (h.Get(echoerror.HeaderSunset) == "Fri, 01 Oct 2027 00:00:00 GMT") {
+			t.Error("Error", tc, h)
+		}
+		if deprecated != (h.Get("Link") == `<https://example.com/errors/v2>; rel="deprecation"`) {
+			t.Error("Error", tc, h)
+		}
+	}
+}